@@ -0,0 +1,159 @@
+package alerts
+
+import (
+	"comparebuddy-backend/config"
+	"comparebuddy-backend/notify"
+	"context"
+	"log"
+	"os"
+	"time"
+)
+
+const defaultInterval = 15 * time.Minute
+
+// Interval - ALERT_INTERVAL (e.g. "5m"), or 15 minutes
+func Interval() time.Duration {
+	if s := os.Getenv("ALERT_INTERVAL"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err == nil && d > 0 {
+			return d
+		}
+		log.Println("⚠️  Invalid ALERT_INTERVAL, using", defaultInterval)
+	}
+	return defaultInterval
+}
+
+// Run - evaluates price alerts every interval until ctx is cancelled
+func Run(ctx context.Context, n notify.Notifier, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := Evaluate(ctx, n); err != nil {
+			log.Println("⚠️  Price alert evaluation failed:", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Evaluate - notifies every active alert whose variant is at or below its
+// threshold and has not been notified at this price or lower. Alerts whose
+// price went back above the threshold are re-armed.
+//
+// Several API instances may evaluate at once: each alert is claimed with a
+// conditional UPDATE before delivery, so only one instance notifies it. A
+// failed delivery releases the claim; a crash between claim and delivery
+// drops that notification rather than sending it twice.
+func Evaluate(ctx context.Context, n notify.Notifier) error {
+	if _, err := config.DB.ExecContext(ctx,
+		`UPDATE price_alerts a
+		JOIN car_variants v ON a.variant_id = v.id
+		SET a.last_notified_price = NULL
+		WHERE a.last_notified_price IS NOT NULL
+		AND (v.price_baht IS NULL OR v.price_baht > a.threshold_baht)`,
+	); err != nil {
+		return err
+	}
+
+	rows, err := config.DB.QueryContext(ctx,
+		`SELECT a.id, a.user_id, a.variant_id, a.threshold_baht,
+			a.last_notified_price, a.last_notified_at,
+			v.name, b.name, m.name, v.price_baht,
+			(SELECT p.previous_price_baht FROM car_variant_prices p
+				WHERE p.variant_id = v.id ORDER BY p.changed_at DESC, p.id DESC LIMIT 1)
+		FROM price_alerts a
+		JOIN car_variants v ON a.variant_id = v.id
+		JOIN car_models m ON v.model_id = m.id
+		JOIN car_brands b ON m.brand_id = b.id
+		WHERE a.active = 1
+		AND v.status <> 'discontinued'
+		AND v.price_baht <= a.threshold_baht
+		AND (a.last_notified_price IS NULL OR v.price_baht < a.last_notified_price)`,
+	)
+	if err != nil {
+		return err
+	}
+
+	var due []dueAlert
+	for rows.Next() {
+		var a dueAlert
+		d := &a.drop
+		if err := rows.Scan(&d.AlertID, &d.UserID, &d.VariantID, &d.ThresholdBaht,
+			&a.lastPrice, &a.lastAt,
+			&d.VariantName, &d.BrandName, &d.ModelName, &d.PriceBaht, &d.PreviousPriceBaht); err != nil {
+			rows.Close()
+			return err
+		}
+		due = append(due, a)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// Deliver after the result set is closed; a failed delivery puts the
+	// alert back as it was so the next run retries it
+	now := time.Now()
+	for _, a := range due {
+		d := a.drop
+		d.DetectedAt = now
+		claimed, err := claim(ctx, d, now)
+		if err != nil {
+			log.Printf("⚠️  Price alert %d not claimed: %v", d.AlertID, err)
+			continue
+		}
+		if !claimed {
+			// Notified meanwhile by another instance
+			continue
+		}
+		if err := n.NotifyPriceDrop(ctx, d); err != nil {
+			log.Printf("⚠️  Price alert %d not delivered: %v", d.AlertID, err)
+			if err := release(a); err != nil {
+				log.Printf("⚠️  Price alert %d not released for retry: %v", d.AlertID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// releaseTimeout - bounds the release of a claim after a failed delivery
+const releaseTimeout = 5 * time.Second
+
+// release - puts a claimed alert back to its state before the claim. It does
+// not use the evaluator's context: a delivery that failed because of shutdown
+// must still be released, or that notification is lost.
+func release(a dueAlert) error {
+	ctx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+	defer cancel()
+	_, err := config.DB.ExecContext(ctx,
+		"UPDATE price_alerts SET last_notified_price = ?, last_notified_at = ? WHERE id = ? AND last_notified_price = ?",
+		a.lastPrice, a.lastAt, a.drop.AlertID, a.drop.PriceBaht,
+	)
+	return err
+}
+
+// dueAlert - an alert to notify and its notified state before the claim
+type dueAlert struct {
+	drop      notify.PriceDrop
+	lastPrice *float64
+	lastAt    *time.Time
+}
+
+// claim - marks the alert notified at d.PriceBaht unless another instance
+// already did; false when it lost the race
+func claim(ctx context.Context, d notify.PriceDrop, now time.Time) (bool, error) {
+	res, err := config.DB.ExecContext(ctx,
+		`UPDATE price_alerts SET last_notified_price = ?, last_notified_at = ?
+		WHERE id = ? AND (last_notified_price IS NULL OR last_notified_price > ?)`,
+		d.PriceBaht, now, d.AlertID, d.PriceBaht,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
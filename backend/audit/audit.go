@@ -0,0 +1,204 @@
+package audit
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Actions
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// ignoredColumns - maintained by MySQL, not worth a diff entry
+var ignoredColumns = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+}
+
+// Row - a snapshot of one table row, column -> value
+type Row map[string]interface{}
+
+// Change - before and after value of one column
+type Change struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// Actor - who made a change: the signed-in user and the X-Request-ID of the request
+type Actor struct {
+	UserID    *int
+	RequestID string
+}
+
+// Querier - *sql.DB or *sql.Tx
+type Querier interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// Diff - the columns whose value differs between before and after. A nil
+// before (create) or after (delete) compares every column against null.
+func Diff(before, after Row) map[string]Change {
+	changes := map[string]Change{}
+	for col, b := range before {
+		if ignoredColumns[col] {
+			continue
+		}
+		if a := after[col]; !sameValue(a, b) {
+			changes[col] = Change{Before: b, After: a}
+		}
+	}
+	for col, a := range after {
+		if _, ok := before[col]; !ok && !ignoredColumns[col] && a != nil {
+			changes[col] = Change{Before: nil, After: a}
+		}
+	}
+	return changes
+}
+
+// Record - writes one audit_log entry for a write to table row rowID. Updates
+// that changed nothing are not recorded.
+func Record(q Querier, actor Actor, table string, rowID int64, action string, before, after Row) error {
+	changes := Diff(before, after)
+	if action == ActionUpdate && len(changes) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	var requestID interface{}
+	if actor.RequestID != "" {
+		requestID = actor.RequestID
+	}
+	_, err = q.Exec(
+		"INSERT INTO audit_log (table_name, row_id, action, user_id, request_id, changes) VALUES (?, ?, ?, ?, ?, ?)",
+		table, rowID, action, actor.UserID, requestID, string(data),
+	)
+	return err
+}
+
+// RecordChanges - Record for every row in before or after, e.g. a cascade
+// that discontinued a brand's models. Rows only in after were created, rows
+// only in before were deleted.
+func RecordChanges(q Querier, actor Actor, table string, before, after map[int64]Row) error {
+	for id, b := range before {
+		action := ActionUpdate
+		a, ok := after[id]
+		if !ok {
+			action = ActionDelete
+		}
+		if err := Record(q, actor, table, id, action, b, a); err != nil {
+			return err
+		}
+	}
+	for id, a := range after {
+		if _, ok := before[id]; !ok {
+			if err := Record(q, actor, table, id, ActionCreate, nil, a); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Snapshot - the current values of row id, or nil when it does not exist
+func Snapshot(q Querier, table string, id int64) (Row, error) {
+	rows, err := Snapshots(q, table, "id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	return rows[id], nil
+}
+
+// Snapshots - every row of table matching where, keyed by id. Values are
+// converted by column type so they read naturally in the diff (DECIMAL as a
+// number, JSON as JSON, BOOLEAN stays 0/1 as MySQL stores it).
+func Snapshots(q Querier, table, where string, args ...interface{}) (map[int64]Row, error) {
+	rows, err := q.Query("SELECT * FROM "+table+" WHERE "+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	out := map[int64]Row{}
+	for rows.Next() {
+		raw := make([]interface{}, len(types))
+		ptrs := make([]interface{}, len(types))
+		for i := range raw {
+			ptrs[i] = &raw[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+
+		row := Row{}
+		for i, t := range types {
+			row[t.Name()] = convert(raw[i], t.DatabaseTypeName())
+		}
+		id, ok := row["id"].(int64)
+		if !ok {
+			return nil, fmt.Errorf("%s: id column is not an integer", table)
+		}
+		out[id] = row
+	}
+	return out, rows.Err()
+}
+
+func convert(v interface{}, dbType string) interface{} {
+	b, ok := v.([]byte)
+	if !ok {
+		return v
+	}
+	s := string(b)
+	switch strings.ToUpper(dbType) {
+	case "DECIMAL", "FLOAT", "DOUBLE":
+		var f float64
+		if _, err := fmt.Sscan(s, &f); err == nil {
+			return f
+		}
+	case "INT", "TINYINT", "SMALLINT", "MEDIUMINT", "BIGINT", "UNSIGNED INT", "UNSIGNED BIGINT":
+		var i int64
+		if _, err := fmt.Sscan(s, &i); err == nil {
+			return i
+		}
+	case "JSON":
+		if json.Valid(b) {
+			return json.RawMessage(append([]byte(nil), b...))
+		}
+	}
+	return s
+}
+
+func sameValue(a, b interface{}) bool {
+	if ta, ok := a.(time.Time); ok {
+		tb, ok := b.(time.Time)
+		return ok && ta.Equal(tb)
+	}
+	if ja, ok := a.(json.RawMessage); ok {
+		jb, ok := b.(json.RawMessage)
+		return ok && jsonEqual(ja, jb)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func jsonEqual(a, b json.RawMessage) bool {
+	var va, vb interface{}
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return string(a) == string(b)
+	}
+	return reflect.DeepEqual(va, vb)
+}
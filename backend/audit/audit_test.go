@@ -0,0 +1,87 @@
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	at := time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		before, after Row
+		want          map[string]Change
+	}{
+		{
+			name:  "create",
+			after: Row{"id": int64(1), "name": "Atto 3", "range_km": nil, "created_at": at},
+			want:  map[string]Change{"id": {nil, int64(1)}, "name": {nil, "Atto 3"}},
+		},
+		{
+			name:   "delete",
+			before: Row{"id": int64(1), "name": "Atto 3", "range_km": nil},
+			want:   map[string]Change{"id": {int64(1), nil}, "name": {"Atto 3", nil}},
+		},
+		{
+			name:   "update",
+			before: Row{"id": int64(1), "price_baht": 1099900.0, "range_km": nil, "updated_at": at},
+			after:  Row{"id": int64(1), "price_baht": 999900.0, "range_km": int64(410), "updated_at": at.Add(time.Hour)},
+			want:   map[string]Change{"price_baht": {1099900.0, 999900.0}, "range_km": {nil, int64(410)}},
+		},
+		{
+			name:   "same instant in another zone",
+			before: Row{"launched_at": at},
+			after:  Row{"launched_at": at.In(time.FixedZone("ICT", 7*3600))},
+			want:   map[string]Change{},
+		},
+		{
+			name:   "JSON compared by value",
+			before: Row{"extra": json.RawMessage(`{"a": 1, "b": [1, 2]}`)},
+			after:  Row{"extra": json.RawMessage(`{"b":[1,2],"a":1}`)},
+			want:   map[string]Change{},
+		},
+		{
+			name:   "JSON changed",
+			before: Row{"extra": json.RawMessage(`{"a":1}`)},
+			after:  Row{"extra": json.RawMessage(`{"a":2}`)},
+			want:   map[string]Change{"extra": {json.RawMessage(`{"a":1}`), json.RawMessage(`{"a":2}`)}},
+		},
+		{
+			name:   "type change is a change",
+			before: Row{"seats": int64(5)},
+			after:  Row{"seats": "5"},
+			want:   map[string]Change{"seats": {int64(5), "5"}},
+		},
+	}
+	for _, tt := range tests {
+		if got := Diff(tt.before, tt.after); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Diff = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		v      interface{}
+		dbType string
+		want   interface{}
+	}{
+		{[]byte("1299000.00"), "DECIMAL", 1299000.0},
+		{[]byte("42"), "BIGINT", int64(42)},
+		{[]byte("1"), "tinyint", int64(1)},
+		{[]byte(`{"a":1}`), "JSON", json.RawMessage(`{"a":1}`)},
+		{[]byte("{broken"), "JSON", "{broken"},
+		{[]byte("abc"), "DECIMAL", "abc"},
+		{[]byte("BYD"), "VARCHAR", "BYD"},
+		{int64(7), "INT", int64(7)},
+		{nil, "VARCHAR", nil},
+	}
+	for _, tt := range tests {
+		if got := convert(tt.v, tt.dbType); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("convert(%v, %s) = %#v, want %#v", tt.v, tt.dbType, got, tt.want)
+		}
+	}
+}
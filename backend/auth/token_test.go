@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func withSecret(t *testing.T, s string) {
+	t.Helper()
+	old := secret
+	secret = []byte(s)
+	t.Cleanup(func() { secret = old })
+}
+
+// signedToken - a token with the given header and claims, signed with the current secret
+func signedToken(t *testing.T, header map[string]string, claims Claims) string {
+	t.Helper()
+	h, err := json.Marshal(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsigned := encodeSegment(h) + "." + encodeSegment(p)
+	return unsigned + "." + sign(unsigned)
+}
+
+func TestAccessTokenRoundTrip(t *testing.T) {
+	withSecret(t, "test-secret")
+
+	token, expiresAt, err := IssueAccessToken(42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Until(expiresAt); d <= 0 || d > AccessTokenTTL {
+		t.Errorf("expiresAt in %v, want within %v", d, AccessTokenTTL)
+	}
+
+	claims, err := ParseAccessToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, err := claims.UserID(); err != nil || id != 42 {
+		t.Errorf("UserID() = %d, %v, want 42", id, err)
+	}
+}
+
+func TestParseAccessTokenRejects(t *testing.T) {
+	withSecret(t, "test-secret")
+	now := time.Now().Unix()
+	valid := Claims{Subject: "7", Issuer: issuer, IssuedAt: now, ExpiresAt: now + 60}
+	hs256 := map[string]string{"alg": "HS256", "typ": "JWT"}
+
+	good := signedToken(t, hs256, valid)
+	parts := strings.Split(good, ".")
+
+	otherSecret := func() string {
+		withSecret(t, "other-secret")
+		defer withSecret(t, "test-secret")
+		return signedToken(t, hs256, valid)
+	}()
+
+	expired := valid
+	expired.ExpiresAt = now - 1
+	foreign := valid
+	foreign.Issuer = "someone-else"
+
+	tests := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{"malformed", "abc", ErrInvalidToken},
+		{"tampered payload", parts[0] + "." + encodeSegment([]byte(`{"sub":"1","iss":"comparebuddy","exp":9999999999}`)) + "." + parts[2], ErrInvalidToken},
+		{"other secret", otherSecret, ErrInvalidToken},
+		{"alg none", signedToken(t, map[string]string{"alg": "none"}, valid), ErrInvalidToken},
+		{"wrong issuer", signedToken(t, hs256, foreign), ErrInvalidToken},
+		{"expired", signedToken(t, hs256, expired), ErrExpiredToken},
+	}
+	for _, tt := range tests {
+		if _, err := ParseAccessToken(tt.token); err != tt.want {
+			t.Errorf("%s: ParseAccessToken error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	if _, err := ParseAccessToken(good); err != nil {
+		t.Errorf("valid token rejected: %v", err)
+	}
+}
+
+func TestRefreshTokenHash(t *testing.T) {
+	token, hash, err := NewRefreshToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash != HashToken(token) || len(hash) != 64 {
+		t.Errorf("hash = %q, want the 64-character SHA-256 of the token", hash)
+	}
+	other, _, err := NewRefreshToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == token {
+		t.Error("NewRefreshToken returned the same token twice")
+	}
+}
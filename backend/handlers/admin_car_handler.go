@@ -0,0 +1,288 @@
+package handlers
+
+import (
+	"comparebuddy-backend/audit"
+	"comparebuddy-backend/config"
+	"comparebuddy-backend/models"
+	"errors"
+	"strconv"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Admin catalog writes. Bodies are validated against the table's columns (for
+// variants: every car_variants field of the spec registry) and ENUM options.
+// DELETE never removes rows: it marks the row discontinued, together with
+// everything below it (a brand's models and variants, a model's variants).
+// Every changed row is recorded in audit_log in the same transaction.
+
+// CreateCarBrand - POST /api/admin/cars/brands
+func CreateCarBrand(c *fiber.Ctx) error {
+	return createCatalogRow(c, brandTable, loadCarBrand)
+}
+
+// ReplaceCarBrand - PUT /api/admin/cars/brands/:id
+func ReplaceCarBrand(c *fiber.Ctx) error {
+	return updateCatalogRow(c, brandTable, writeReplace, loadCarBrand)
+}
+
+// UpdateCarBrand - PATCH /api/admin/cars/brands/:id
+func UpdateCarBrand(c *fiber.Ctx) error {
+	return updateCatalogRow(c, brandTable, writeUpdate, loadCarBrand)
+}
+
+// DeleteCarBrand - DELETE /api/admin/cars/brands/:id
+func DeleteCarBrand(c *fiber.Ctx) error {
+	return discontinueCatalogRow(c, brandTable,
+		discontinueStep{"car_brands", "id = ?"},
+		discontinueStep{"car_models", "brand_id = ?"},
+		discontinueStep{"car_variants", "model_id IN (SELECT id FROM car_models WHERE brand_id = ?)"},
+	)
+}
+
+// CreateCarModel - POST /api/admin/cars/models
+func CreateCarModel(c *fiber.Ctx) error {
+	return createCatalogRow(c, modelTable, loadCarModel)
+}
+
+// ReplaceCarModel - PUT /api/admin/cars/models/:id
+func ReplaceCarModel(c *fiber.Ctx) error {
+	return updateCatalogRow(c, modelTable, writeReplace, loadCarModel)
+}
+
+// UpdateCarModel - PATCH /api/admin/cars/models/:id
+func UpdateCarModel(c *fiber.Ctx) error {
+	return updateCatalogRow(c, modelTable, writeUpdate, loadCarModel)
+}
+
+// DeleteCarModel - DELETE /api/admin/cars/models/:id
+func DeleteCarModel(c *fiber.Ctx) error {
+	return discontinueCatalogRow(c, modelTable,
+		discontinueStep{"car_models", "id = ?"},
+		discontinueStep{"car_variants", "model_id = ?"},
+	)
+}
+
+// CreateCarVariant - POST /api/admin/cars/variants
+func CreateCarVariant(c *fiber.Ctx) error {
+	return createCatalogRow(c, variantTable, loadCarVariant)
+}
+
+// ReplaceCarVariant - PUT /api/admin/cars/variants/:id
+// Spec fields left out of the body are reset to their column default.
+func ReplaceCarVariant(c *fiber.Ctx) error {
+	return updateCatalogRow(c, variantTable, writeReplace, loadCarVariant)
+}
+
+// UpdateCarVariant - PATCH /api/admin/cars/variants/:id
+// e.g. {"price_baht": 1099000, "aeb": true}
+func UpdateCarVariant(c *fiber.Ctx) error {
+	return updateCatalogRow(c, variantTable, writeUpdate, loadCarVariant)
+}
+
+// DeleteCarVariant - DELETE /api/admin/cars/variants/:id
+func DeleteCarVariant(c *fiber.Ctx) error {
+	return discontinueCatalogRow(c, variantTable,
+		discontinueStep{"car_variants", "id = ?"},
+	)
+}
+
+func createCatalogRow(c *fiber.Ctx, t catalogTable, load func(id int) (interface{}, error)) error {
+	values, err := t.parseWrite(c.Body(), writeCreate)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to create " + t.noun()})
+	}
+	defer tx.Rollback()
+
+	id, err := t.insertRow(tx, values)
+	if err != nil {
+		if msg := clientWriteError(err); msg != "" {
+			return c.Status(400).JSON(fiber.Map{"error": msg})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to create " + t.noun()})
+	}
+	after, err := audit.Snapshot(tx, t.Table, id)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to create " + t.noun()})
+	}
+	if err := audit.Record(tx, auditActor(c), t.Table, id, audit.ActionCreate, nil, after); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to create " + t.noun()})
+	}
+	if err := tx.Commit(); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to create " + t.noun()})
+	}
+	t.written()
+
+	row, err := load(int(id))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to load " + t.noun()})
+	}
+	return c.Status(201).JSON(row)
+}
+
+func updateCatalogRow(c *fiber.Ctx, t catalogTable, mode int, load func(id int) (interface{}, error)) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid " + t.noun() + " ID"})
+	}
+
+	values, err := t.parseWrite(c.Body(), mode)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update " + t.noun()})
+	}
+	defer tx.Rollback()
+
+	before, err := audit.Snapshots(tx, t.Table, "id = ? FOR UPDATE", id)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update " + t.noun()})
+	}
+	if before[int64(id)] == nil {
+		return c.Status(404).JSON(fiber.Map{"error": t.Label + " not found"})
+	}
+
+	if err := t.updateRow(tx, id, values); err != nil {
+		if msg := clientWriteError(err); msg != "" {
+			return c.Status(400).JSON(fiber.Map{"error": msg})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update " + t.noun()})
+	}
+	after, err := audit.Snapshots(tx, t.Table, "id = ?", id)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update " + t.noun()})
+	}
+	if err := audit.RecordChanges(tx, auditActor(c), t.Table, before, after); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update " + t.noun()})
+	}
+	if err := tx.Commit(); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update " + t.noun()})
+	}
+	t.written()
+
+	row, err := load(id)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to load " + t.noun()})
+	}
+	return c.JSON(row)
+}
+
+// discontinueStep - rows of Table matching Where (which takes the id of the
+// deleted row) are set to status 'discontinued'
+type discontinueStep struct {
+	Table string
+	Where string
+}
+
+// discontinueCatalogRow - runs the steps in one transaction; the first step
+// must select the row itself
+func discontinueCatalogRow(c *fiber.Ctx, t catalogTable, steps ...discontinueStep) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid " + t.noun() + " ID"})
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to discontinue " + t.noun()})
+	}
+	defer tx.Rollback()
+
+	actor := auditActor(c)
+	for i, step := range steps {
+		before, err := audit.Snapshots(tx, step.Table, step.Where+" FOR UPDATE", id)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to discontinue " + t.noun()})
+		}
+		if i == 0 && len(before) == 0 {
+			return c.Status(404).JSON(fiber.Map{"error": t.Label + " not found"})
+		}
+		if _, err := tx.Exec("UPDATE "+step.Table+" SET status = 'discontinued' WHERE "+step.Where, id); err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to discontinue " + t.noun()})
+		}
+		after, err := audit.Snapshots(tx, step.Table, step.Where, id)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to discontinue " + t.noun()})
+		}
+		if err := audit.RecordChanges(tx, actor, step.Table, before, after); err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to discontinue " + t.noun()})
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to discontinue " + t.noun()})
+	}
+	t.written()
+
+	return c.JSON(fiber.Map{"message": t.Label + " discontinued", "id": id})
+}
+
+// deleteCatalogRow - removes the row; for tables without a status column
+func deleteCatalogRow(c *fiber.Ctx, t catalogTable) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid " + t.noun() + " ID"})
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete " + t.noun()})
+	}
+	defer tx.Rollback()
+
+	before, err := audit.Snapshots(tx, t.Table, "id = ? FOR UPDATE", id)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete " + t.noun()})
+	}
+	if before[int64(id)] == nil {
+		return c.Status(404).JSON(fiber.Map{"error": t.Label + " not found"})
+	}
+
+	if _, err := tx.Exec("DELETE FROM "+t.Table+" WHERE id = ?", id); err != nil {
+		var me *mysql.MySQLError
+		if errors.As(err, &me) && me.Number == 1451 {
+			return c.Status(409).JSON(fiber.Map{"error": t.Label + " is still referenced by other rows"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete " + t.noun()})
+	}
+	if err := audit.RecordChanges(tx, auditActor(c), t.Table, before, nil); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete " + t.noun()})
+	}
+	if err := tx.Commit(); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete " + t.noun()})
+	}
+	t.written()
+
+	return c.JSON(fiber.Map{"message": t.Label + " deleted", "id": id})
+}
+
+func loadCarBrand(id int) (interface{}, error) {
+	var b models.CarBrand
+	err := config.DB.QueryRow("SELECT id, name, name_th, country, logo_url, status FROM car_brands WHERE id = ?", id).
+		Scan(&b.ID, &b.Name, &b.NameTh, &b.Country, &b.LogoURL, &b.Status)
+	return b, err
+}
+
+func loadCarModel(id int) (interface{}, error) {
+	var m models.CarModel
+	err := config.DB.QueryRow(
+		"SELECT m.id, m.brand_id, m.name, m.powertrain_type, m.body_type, m.segment, m.year_launched, m.status, b.name FROM car_models m JOIN car_brands b ON m.brand_id = b.id WHERE m.id = ?",
+		id,
+	).Scan(&m.ID, &m.BrandID, &m.Name, &m.PowertrainType, &m.BodyType, &m.Segment, &m.YearLaunched, &m.Status, &m.BrandName)
+	return m, err
+}
+
+func loadCarVariant(id int) (interface{}, error) {
+	return scanVariant(config.DB.QueryRow(
+		"SELECT "+variantColumns+" FROM car_variants v JOIN car_models m ON v.model_id = m.id JOIN car_brands b ON m.brand_id = b.id WHERE v.id = ?",
+		id,
+	))
+}
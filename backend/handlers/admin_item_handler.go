@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"comparebuddy-backend/config"
+	"comparebuddy-backend/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Admin writes for the generic item catalog. Items and categories have no
+// status column, so DELETE removes the row (the audit log keeps its values).
+
+// CreateItem - POST /api/admin/items
+func CreateItem(c *fiber.Ctx) error {
+	return createCatalogRow(c, itemTable, loadItem)
+}
+
+// ReplaceItem - PUT /api/admin/items/:id
+func ReplaceItem(c *fiber.Ctx) error {
+	return updateCatalogRow(c, itemTable, writeReplace, loadItem)
+}
+
+// UpdateItem - PATCH /api/admin/items/:id
+func UpdateItem(c *fiber.Ctx) error {
+	return updateCatalogRow(c, itemTable, writeUpdate, loadItem)
+}
+
+// DeleteItem - DELETE /api/admin/items/:id
+func DeleteItem(c *fiber.Ctx) error {
+	return deleteCatalogRow(c, itemTable)
+}
+
+// CreateCategory - POST /api/admin/categories
+func CreateCategory(c *fiber.Ctx) error {
+	return createCatalogRow(c, categoryTable, loadCategory)
+}
+
+// ReplaceCategory - PUT /api/admin/categories/:id
+func ReplaceCategory(c *fiber.Ctx) error {
+	return updateCatalogRow(c, categoryTable, writeReplace, loadCategory)
+}
+
+// UpdateCategory - PATCH /api/admin/categories/:id
+func UpdateCategory(c *fiber.Ctx) error {
+	return updateCatalogRow(c, categoryTable, writeUpdate, loadCategory)
+}
+
+// DeleteCategory - DELETE /api/admin/categories/:id
+// Fails with 409 while items still belong to the category.
+func DeleteCategory(c *fiber.Ctx) error {
+	return deleteCatalogRow(c, categoryTable)
+}
+
+func loadItem(id int) (interface{}, error) {
+	var item models.Item
+	err := config.DB.QueryRow(
+		"SELECT id, category_id, brand, name, COALESCE(duration, ''), price, COALESCE(field, '') FROM items WHERE id = ?",
+		id,
+	).Scan(&item.ID, &item.CategoryID, &item.Brand, &item.Name, &item.Duration, &item.Price, &item.Field)
+	return item, err
+}
+
+func loadCategory(id int) (interface{}, error) {
+	var cat models.Category
+	err := config.DB.QueryRow(
+		"SELECT id, main_category_id, name, COALESCE(name_en, '') FROM categories WHERE id = ?",
+		id,
+	).Scan(&cat.ID, &cat.MainCategoryID, &cat.Name, &cat.NameEn)
+	return cat, err
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"comparebuddy-backend/config"
+	"comparebuddy-backend/middleware"
+	"comparebuddy-backend/models"
+	"database/sql"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PriceAlertRequest struct {
+	VariantID     int     `json:"variant_id"`
+	ThresholdBaht float64 `json:"threshold_baht"`
+}
+
+// GetPriceAlerts - GET /api/me/alerts
+func GetPriceAlerts(c *fiber.Ctx) error {
+	user := middleware.CurrentUser(c)
+
+	rows, err := config.DB.Query(
+		`SELECT a.id, a.user_id, a.variant_id, a.threshold_baht, a.active,
+			a.last_notified_price, a.last_notified_at, a.created_at,
+			v.name, b.name, m.name, v.price_baht
+		FROM price_alerts a
+		JOIN car_variants v ON a.variant_id = v.id
+		JOIN car_models m ON v.model_id = m.id
+		JOIN car_brands b ON m.brand_id = b.id
+		WHERE a.user_id = ?
+		ORDER BY a.created_at DESC, a.id DESC`,
+		user.ID,
+	)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch alerts"})
+	}
+	defer rows.Close()
+
+	alerts := []models.PriceAlert{}
+	for rows.Next() {
+		var a models.PriceAlert
+		if err := rows.Scan(&a.ID, &a.UserID, &a.VariantID, &a.ThresholdBaht, &a.Active,
+			&a.LastNotifiedPrice, &a.LastNotifiedAt, &a.CreatedAt,
+			&a.VariantName, &a.BrandName, &a.ModelName, &a.PriceBaht); err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch alerts"})
+		}
+		alerts = append(alerts, a)
+	}
+
+	return c.JSON(alerts)
+}
+
+// CreatePriceAlert - POST /api/me/alerts
+// Alerts are for favorited variants only. One alert per variant: posting again
+// for the same variant replaces the threshold and re-arms the alert.
+func CreatePriceAlert(c *fiber.Ctx) error {
+	user := middleware.CurrentUser(c)
+
+	var req PriceAlertRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if req.VariantID <= 0 {
+		return c.Status(400).JSON(fiber.Map{"error": "variant_id is required"})
+	}
+	if req.ThresholdBaht <= 0 {
+		return c.Status(400).JSON(fiber.Map{"error": "threshold_baht must be greater than 0"})
+	}
+
+	var favorited bool
+	err := config.DB.QueryRow(
+		"SELECT EXISTS(SELECT 1 FROM user_car_favorites f WHERE f.user_id = ? AND f.variant_id = v.id) FROM car_variants v WHERE v.id = ?",
+		user.ID, req.VariantID,
+	).Scan(&favorited)
+	if err == sql.ErrNoRows {
+		return c.Status(404).JSON(fiber.Map{"error": "Variant not found"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to create alert"})
+	}
+	if !favorited {
+		return c.Status(409).JSON(fiber.Map{"error": "Add the variant to your favorites before creating an alert"})
+	}
+
+	// A single upsert on unique_alert, so concurrent posts for the same variant
+	// cannot both insert. LAST_INSERT_ID(id) reports the existing row's id.
+	result, err := config.DB.Exec(
+		`INSERT INTO price_alerts (user_id, variant_id, threshold_baht) VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE id = LAST_INSERT_ID(id), threshold_baht = VALUES(threshold_baht),
+			active = 1, last_notified_price = NULL, last_notified_at = NULL`,
+		user.ID, req.VariantID, req.ThresholdBaht,
+	)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to create alert"})
+	}
+	id, _ := result.LastInsertId()
+	// 1 row affected: inserted; 2: updated; 0: updated to the same values
+	if n, _ := result.RowsAffected(); n == 1 {
+		return c.Status(201).JSON(fiber.Map{"message": "Alert created", "id": id, "variant_id": req.VariantID})
+	}
+	return c.JSON(fiber.Map{"message": "Alert updated", "id": id, "variant_id": req.VariantID})
+}
+
+// DeletePriceAlert - DELETE /api/me/alerts/:id
+func DeletePriceAlert(c *fiber.Ctx) error {
+	user := middleware.CurrentUser(c)
+
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid alert id"})
+	}
+
+	result, err := config.DB.Exec("DELETE FROM price_alerts WHERE id = ? AND user_id = ?", id, user.ID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete alert"})
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return c.Status(404).JSON(fiber.Map{"error": "Alert not found"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Alert deleted", "id": id})
+}
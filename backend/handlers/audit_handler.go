@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"comparebuddy-backend/audit"
+	"comparebuddy-backend/config"
+	"comparebuddy-backend/middleware"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// auditSorts - ?sort= keys of the audit endpoints; newest first by default
+var auditSorts = map[string][]string{
+	"created_at": {"a.created_at", "a.id"},
+}
+
+// auditTables - tables whose writes are audited, accepted by ?table=
+var auditTables = map[string]bool{
+	"car_brands":   true,
+	"car_models":   true,
+	"car_variants": true,
+	"items":        true,
+	"categories":   true,
+}
+
+type auditEntry struct {
+	ID        int64                   `json:"id"`
+	Table     string                  `json:"table"`
+	RowID     int64                   `json:"row_id"`
+	Action    string                  `json:"action"`
+	UserID    *int                    `json:"user_id"`
+	Username  *string                 `json:"username"`
+	RequestID *string                 `json:"request_id"`
+	Changes   map[string]audit.Change `json:"changes"`
+	CreatedAt time.Time               `json:"created_at"`
+}
+
+// GetAuditLog - GET /api/admin/audit?table=car_variants&row_id=12&user_id=3&action=update&field=price_baht&request_id=...&since=2025-01-01&until=...
+// Paginated like the other list endpoints; since/until are RFC 3339 or YYYY-MM-DD.
+func GetAuditLog(c *fiber.Ctx) error {
+	where := []string{"1 = 1"}
+	args := []interface{}{}
+
+	if table := c.Query("table"); table != "" {
+		if !auditTables[table] {
+			return c.Status(400).JSON(fiber.Map{"error": "table must be one of: car_brands, car_models, car_variants, items, categories"})
+		}
+		where = append(where, "a.table_name = ?")
+		args = append(args, table)
+	}
+	for _, p := range []struct{ param, column string }{{"row_id", "a.row_id"}, {"user_id", "a.user_id"}} {
+		if s := c.Query(p.param); s != "" {
+			n, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return c.Status(400).JSON(fiber.Map{"error": p.param + " must be an integer"})
+			}
+			where = append(where, p.column+" = ?")
+			args = append(args, n)
+		}
+	}
+	if action := c.Query("action"); action != "" {
+		if action != audit.ActionCreate && action != audit.ActionUpdate && action != audit.ActionDelete {
+			return c.Status(400).JSON(fiber.Map{"error": "action must be one of: create, update, delete"})
+		}
+		where = append(where, "a.action = ?")
+		args = append(args, action)
+	}
+	if requestID := c.Query("request_id"); requestID != "" {
+		where = append(where, "a.request_id = ?")
+		args = append(args, requestID)
+	}
+	if field := c.Query("field"); field != "" {
+		where = append(where, "JSON_CONTAINS_PATH(a.changes, 'one', ?)")
+		args = append(args, "$."+strconv.Quote(field))
+	}
+	for _, p := range []struct{ param, op string }{{"since", ">="}, {"until", "<"}} {
+		if s := c.Query(p.param); s != "" {
+			t, err := parseAuditTime(s)
+			if err != nil {
+				return c.Status(400).JSON(fiber.Map{"error": p.param + " must be RFC 3339 or YYYY-MM-DD"})
+			}
+			where = append(where, "a.created_at "+p.op+" ?")
+			args = append(args, t)
+		}
+	}
+
+	result, status, err := auditPage(c, " WHERE "+strings.Join(where, " AND "), args)
+	if status == 400 {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch audit log"})
+	}
+	return c.JSON(result)
+}
+
+// GetVariantHistory - GET /api/admin/cars/variants/:id/history
+// Every recorded change of one variant, newest first, paginated.
+func GetVariantHistory(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid variant ID"})
+	}
+
+	result, status, err := auditPage(c, " WHERE a.table_name = 'car_variants' AND a.row_id = ?", []interface{}{id})
+	if status == 400 {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch variant history"})
+	}
+	result["variant_id"] = id
+	return c.JSON(result)
+}
+
+// auditPage - one page of audit entries matching where. On failure the
+// status is 400 for invalid paging parameters, 500 otherwise.
+func auditPage(c *fiber.Ctx, where string, args []interface{}) (fiber.Map, int, error) {
+	list, err := parseListQuery(c, auditSorts, "a.id", "-created_at", 50)
+	if err != nil {
+		return nil, 400, err
+	}
+
+	from := " FROM audit_log a LEFT JOIN users u ON a.user_id = u.id" + where
+	total, err := countRows(from, args)
+	if err != nil {
+		return nil, 500, err
+	}
+
+	page, pageArgs := list.page()
+	rows, err := config.DB.Query(
+		"SELECT a.id, a.table_name, a.row_id, a.action, a.user_id, u.username, a.request_id, a.changes, a.created_at"+list.keyColumns()+from+page,
+		append(args, pageArgs...)...,
+	)
+	if err != nil {
+		return nil, 500, err
+	}
+	defer rows.Close()
+
+	entries := []auditEntry{}
+	for rows.Next() {
+		var e auditEntry
+		var changes []byte
+		dest := []interface{}{&e.ID, &e.Table, &e.RowID, &e.Action, &e.UserID, &e.Username, &e.RequestID, &changes, &e.CreatedAt}
+		if err := rows.Scan(append(dest, list.scanKey()...)...); err != nil {
+			return nil, 500, err
+		}
+		if err := json.Unmarshal(changes, &e.Changes); err != nil {
+			return nil, 500, err
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 500, err
+	}
+
+	return listEnvelope(list, entries, total), 200, nil
+}
+
+func parseAuditTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02", s, time.Local)
+}
+
+// auditActor - the signed-in user and request ID recorded with a write
+func auditActor(c *fiber.Ctx) audit.Actor {
+	var actor audit.Actor
+	if user := middleware.CurrentUser(c); user != nil {
+		actor.UserID = &user.ID
+	}
+	actor.RequestID = middleware.CurrentRequestID(c)
+	return actor
+}
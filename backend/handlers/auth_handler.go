@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"comparebuddy-backend/auth"
 	"comparebuddy-backend/config"
 	"comparebuddy-backend/models"
 	"database/sql"
@@ -9,6 +10,7 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/gofiber/fiber/v2"
 	"golang.org/x/crypto/bcrypt"
@@ -30,6 +32,24 @@ type GoogleLoginRequest struct {
 	IDToken string `json:"id_token"`
 }
 
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+	All          bool   `json:"all"`
+}
+
+// sqlExecer and sqlQueryer are satisfied by both *sql.DB and *sql.Tx
+type sqlExecer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+type sqlQueryer interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
 func Register(c *fiber.Ctx) error {
 	var req RegisterRequest
 	if err := c.BodyParser(&req); err != nil {
@@ -84,10 +104,7 @@ func Register(c *fiber.Ctx) error {
 		DisplayName: displayName,
 	}
 
-	return c.Status(201).JSON(fiber.Map{
-		"message": "User registered successfully",
-		"user":    user,
-	})
+	return respondWithSession(c, 201, "User registered successfully", user)
 }
 
 func Login(c *fiber.Ctx) error {
@@ -119,10 +136,7 @@ func Login(c *fiber.Ctx) error {
 		return c.Status(401).JSON(fiber.Map{"error": "Invalid username or password"})
 	}
 
-	return c.JSON(fiber.Map{
-		"message": "Login successful",
-		"user":    user,
-	})
+	return respondWithSession(c, 200, "Login successful", user)
 }
 
 func GoogleLogin(c *fiber.Ctx) error {
@@ -171,10 +185,178 @@ func GoogleLogin(c *fiber.Ctx) error {
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to query user"})
 	}
 
-	return c.JSON(fiber.Map{
-		"message": "Login successful",
-		"user":    user,
-	})
+	return respondWithSession(c, 200, "Login successful", user)
+}
+
+// RefreshSession - POST /api/auth/refresh
+// Rotates the refresh token: the presented token is revoked and a new pair is issued.
+// Presenting an already revoked token revokes every active session of that user.
+func RefreshSession(c *fiber.Ctx) error {
+	var req RefreshRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if req.RefreshToken == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "Refresh token is required"})
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to refresh session"})
+	}
+	defer tx.Rollback()
+
+	var tokenID, userID int
+	var revokedAt sql.NullTime
+	var expired bool
+	err = tx.QueryRow(
+		"SELECT id, user_id, revoked_at, expires_at <= NOW() FROM refresh_tokens WHERE token_hash = ? FOR UPDATE",
+		auth.HashToken(req.RefreshToken),
+	).Scan(&tokenID, &userID, &revokedAt, &expired)
+	if err == sql.ErrNoRows {
+		return c.Status(401).JSON(fiber.Map{"error": "Invalid refresh token"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to refresh session"})
+	}
+
+	if revokedAt.Valid {
+		// Token reuse: someone is replaying a rotated token, end all sessions
+		if _, err := tx.Exec("UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = ? AND revoked_at IS NULL", userID); err == nil {
+			tx.Commit()
+		}
+		return c.Status(401).JSON(fiber.Map{"error": "Refresh token has been revoked"})
+	}
+	if expired {
+		return c.Status(401).JSON(fiber.Map{"error": "Refresh token has expired"})
+	}
+
+	user, err := findUserByID(tx, userID)
+	if err == sql.ErrNoRows {
+		return c.Status(401).JSON(fiber.Map{"error": "Invalid refresh token"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to query user"})
+	}
+
+	refreshToken, newID, err := issueRefreshToken(tx, userID, c.Get("User-Agent"))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to refresh session"})
+	}
+	if _, err := tx.Exec("UPDATE refresh_tokens SET revoked_at = NOW(), replaced_by = ? WHERE id = ?", newID, tokenID); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to refresh session"})
+	}
+
+	accessToken, _, err := auth.IssueAccessToken(userID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to issue access token"})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to refresh session"})
+	}
+
+	return c.JSON(sessionPayload("Session refreshed", user, accessToken, refreshToken))
+}
+
+// Logout - POST /api/auth/logout
+// Revokes the given refresh token, or every session of its owner when all=true.
+func Logout(c *fiber.Ctx) error {
+	var req LogoutRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if req.RefreshToken == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "Refresh token is required"})
+	}
+
+	hash := auth.HashToken(req.RefreshToken)
+
+	var err error
+	if req.All {
+		_, err = config.DB.Exec(
+			"UPDATE refresh_tokens SET revoked_at = NOW() WHERE revoked_at IS NULL AND user_id = (SELECT user_id FROM (SELECT user_id FROM refresh_tokens WHERE token_hash = ?) t)",
+			hash,
+		)
+	} else {
+		_, err = config.DB.Exec("UPDATE refresh_tokens SET revoked_at = NOW() WHERE token_hash = ? AND revoked_at IS NULL", hash)
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to log out"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Logged out"})
+}
+
+// respondWithSession - issues a fresh token pair for the user and writes the auth response
+func respondWithSession(c *fiber.Ctx, status int, message string, user models.User) error {
+	accessToken, _, err := auth.IssueAccessToken(user.ID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to issue access token"})
+	}
+
+	refreshToken, _, err := issueRefreshToken(config.DB, user.ID, c.Get("User-Agent"))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to issue refresh token"})
+	}
+
+	return c.Status(status).JSON(sessionPayload(message, user, accessToken, refreshToken))
+}
+
+func sessionPayload(message string, user models.User, accessToken, refreshToken string) fiber.Map {
+	return fiber.Map{
+		"message":       message,
+		"user":          user,
+		"access_token":  accessToken,
+		"refresh_token": refreshToken,
+		"token_type":    "Bearer",
+		"expires_in":    int(auth.AccessTokenTTL.Seconds()),
+	}
+}
+
+// truncateRunes - s cut to at most n characters, never inside a UTF-8 sequence
+// (VARCHAR lengths count characters)
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	i := 0
+	for count := 0; count < n; count++ {
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+	}
+	return s[:i]
+}
+
+// issueRefreshToken - stores the hash of a new refresh token and returns the plain token
+func issueRefreshToken(db sqlExecer, userID int, userAgent string) (string, int64, error) {
+	token, hash, err := auth.NewRefreshToken()
+	if err != nil {
+		return "", 0, err
+	}
+
+	userAgent = truncateRunes(strings.ToValidUTF8(userAgent, ""), 255)
+
+	result, err := db.Exec(
+		"INSERT INTO refresh_tokens (user_id, token_hash, expires_at, user_agent) VALUES (?, ?, DATE_ADD(NOW(), INTERVAL ? SECOND), ?)",
+		userID, hash, int(auth.RefreshTokenTTL.Seconds()), userAgent,
+	)
+	if err != nil {
+		return "", 0, err
+	}
+
+	id, err := result.LastInsertId()
+	return token, id, err
+}
+
+// findUserByID - loads the public profile of a user
+func findUserByID(q sqlQueryer, id int) (models.User, error) {
+	var user models.User
+	err := q.QueryRow(
+		"SELECT id, username, email, display_name, COALESCE(google_id, ''), COALESCE(avatar_url, ''), created_at FROM users WHERE id = ?",
+		id,
+	).Scan(&user.ID, &user.Username, &user.Email, &user.DisplayName, &user.GoogleID, &user.AvatarURL, &user.CreatedAt)
+	return user, err
 }
 
 type googleTokenInfo struct {
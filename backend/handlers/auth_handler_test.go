@@ -0,0 +1,22 @@
+package handlers
+
+import "testing"
+
+func TestTruncateRunes(t *testing.T) {
+	tests := []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{"Mozilla/5.0", 255, "Mozilla/5.0"},
+		{"Mozilla/5.0", 7, "Mozilla"},
+		{"แอปคอมแพร์บัดดี้", 3, "แอป"},
+		{"añb", 2, "añ"},
+		{"", 5, ""},
+	}
+	for _, tt := range tests {
+		if got := truncateRunes(tt.s, tt.n); got != tt.want {
+			t.Errorf("truncateRunes(%q, %d) = %q, want %q", tt.s, tt.n, got, tt.want)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package handlers
 import (
 	"comparebuddy-backend/config"
 	"comparebuddy-backend/models"
+	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
@@ -10,7 +12,7 @@ import (
 
 // GetCarBrands - GET /api/cars/brands
 func GetCarBrands(c *fiber.Ctx) error {
-	rows, err := config.DB.Query("SELECT id, name, name_th, country, logo_url FROM car_brands ORDER BY name")
+	rows, err := config.DB.Query("SELECT id, name, name_th, country, logo_url, status FROM car_brands ORDER BY name")
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch car brands"})
 	}
@@ -19,7 +21,7 @@ func GetCarBrands(c *fiber.Ctx) error {
 	var brands []models.CarBrand
 	for rows.Next() {
 		var b models.CarBrand
-		rows.Scan(&b.ID, &b.Name, &b.NameTh, &b.Country, &b.LogoURL)
+		rows.Scan(&b.ID, &b.Name, &b.NameTh, &b.Country, &b.LogoURL, &b.Status)
 		brands = append(brands, b)
 	}
 
@@ -31,8 +33,8 @@ func GetCarBrandByID(c *fiber.Ctx) error {
 	id := c.Params("id")
 
 	var brand models.CarBrand
-	err := config.DB.QueryRow("SELECT id, name, name_th, country, logo_url FROM car_brands WHERE id = ?", id).
-		Scan(&brand.ID, &brand.Name, &brand.NameTh, &brand.Country, &brand.LogoURL)
+	err := config.DB.QueryRow("SELECT id, name, name_th, country, logo_url, status FROM car_brands WHERE id = ?", id).
+		Scan(&brand.ID, &brand.Name, &brand.NameTh, &brand.Country, &brand.LogoURL, &brand.Status)
 	if err != nil {
 		return c.Status(404).JSON(fiber.Map{"error": "Brand not found"})
 	}
@@ -56,52 +58,63 @@ func GetCarBrandByID(c *fiber.Ctx) error {
 	return c.JSON(result)
 }
 
-// GetCarModels - GET /api/cars/models?brand_id=&powertrain_type=&body_type=&segment=
+// GetCarModels - GET /api/cars/models?brand_id=&powertrain_type=&body_type=&segment=&sort=&limit=&cursor=
 func GetCarModels(c *fiber.Ctx) error {
+	list, err := parseListQuery(c, modelSorts, "m.id", "name", 50)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
 	brandID := c.Query("brand_id")
 	powertrainType := c.Query("powertrain_type")
 	bodyType := c.Query("body_type")
 	segment := c.Query("segment")
 
-	query := "SELECT m.id, m.brand_id, m.name, m.powertrain_type, m.body_type, m.segment, m.year_launched, m.status, b.name FROM car_models m JOIN car_brands b ON m.brand_id = b.id WHERE 1=1"
+	from := " FROM car_models m JOIN car_brands b ON m.brand_id = b.id WHERE 1=1"
 	args := []interface{}{}
 
 	if brandID != "" {
-		query += " AND m.brand_id = ?"
+		from += " AND m.brand_id = ?"
 		args = append(args, brandID)
 	}
 	if powertrainType != "" {
-		query += " AND m.powertrain_type = ?"
+		from += " AND m.powertrain_type = ?"
 		args = append(args, powertrainType)
 	}
 	if bodyType != "" {
-		query += " AND m.body_type = ?"
+		from += " AND m.body_type = ?"
 		args = append(args, bodyType)
 	}
 	if segment != "" {
-		query += " AND m.segment = ?"
+		from += " AND m.segment = ?"
 		args = append(args, segment)
 	}
 
-	query += " ORDER BY b.name, m.name"
+	total, err := countRows(from, args)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch car models"})
+	}
 
-	rows, err := config.DB.Query(query, args...)
+	page, pageArgs := list.page()
+	query := "SELECT m.id, m.brand_id, m.name, m.powertrain_type, m.body_type, m.segment, m.year_launched, m.status, b.name" + list.keyColumns() + from + page
+	rows, err := config.DB.Query(query, append(args, pageArgs...)...)
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch car models"})
 	}
 	defer rows.Close()
 
-	var carModels []models.CarModel
+	carModels := []models.CarModel{}
 	for rows.Next() {
 		var m models.CarModel
-		rows.Scan(&m.ID, &m.BrandID, &m.Name, &m.PowertrainType, &m.BodyType, &m.Segment, &m.YearLaunched, &m.Status, &m.BrandName)
+		rows.Scan(append([]interface{}{&m.ID, &m.BrandID, &m.Name, &m.PowertrainType, &m.BodyType, &m.Segment, &m.YearLaunched, &m.Status, &m.BrandName}, list.scanKey()...)...)
 		carModels = append(carModels, m)
 	}
 
-	return c.JSON(carModels)
+	return c.JSON(listEnvelope(list, carModels, total))
 }
 
 // GetCarModelByID - GET /api/cars/models/:id
+// Each variant carries its ordered gallery and colour options.
 func GetCarModelByID(c *fiber.Ctx) error {
 	id := c.Params("id")
 
@@ -123,257 +136,281 @@ func GetCarModelByID(c *fiber.Ctx) error {
 	}
 	defer rows.Close()
 
-	result := models.CarModelWithVariants{CarModel: m}
+	var variants []models.CarVariantSummary
 	for rows.Next() {
 		var v models.CarVariantSummary
 		rows.Scan(&v.ID, &v.ModelID, &v.Name, &v.PriceBaht, &v.Status)
-		result.Variants = append(result.Variants, v)
+		variants = append(variants, v)
+	}
+	markFavoriteSummaries(c, variants)
+
+	result := models.CarModelWithVariants{CarModel: m}
+	if result.Variants, err = attachSummaryMedia(variants); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch images and colors"})
 	}
 
 	return c.JSON(result)
 }
 
-// variantColumns - all columns for car_variants full spec query
-const variantColumns = `v.id, v.model_id, v.name, v.price_baht, v.status,
-b.name, m.name, m.powertrain_type, m.body_type,
-v.battery_capacity_kwh, v.battery_type, v.motor_power_kw, v.motor_torque_nm,
-v.front_motor_kw, v.rear_motor_kw, v.range_km, v.range_standard,
-v.ac_charge_kw, v.dc_charge_kw, v.ac_charge_time_hrs, v.dc_charge_time_mins,
-v.charging_port, v.v2l, v.v2g, v.heat_pump, v.battery_preconditioning,
-v.displacement_cc, v.engine_type, v.horsepower, v.engine_torque_nm,
-v.fuel_type, v.fuel_tank_liters, v.fuel_consumption_kml, v.turbo,
-v.transmission, v.transmission_speeds,
-v.system_power_hp, v.system_torque_nm, v.ev_range_km,
-v.top_speed_kmh, v.acceleration_0_100,
-v.length_mm, v.width_mm, v.height_mm, v.wheelbase_mm, v.ground_clearance_mm,
-v.curb_weight_kg, v.gross_weight_kg, v.trunk_capacity_liters, v.trunk_max_liters, v.frunk_capacity_liters,
-v.drive_type, v.front_suspension, v.rear_suspension, v.front_brakes, v.rear_brakes,
-v.tire_size_front, v.tire_size_rear, v.spare_tire,
-v.airbags, v.abs, v.esc, v.traction_control, v.hill_start_assist, v.hill_descent_control,
-v.tpms, v.isofix, v.parking_sensor_front, v.parking_sensor_rear,
-v.camera_rear, v.camera_360, v.auto_parking,
-v.aeb, v.fcw, v.lka, v.ldw, v.bsd, v.rcta, v.acc, v.acc_stop_go,
-v.driver_monitoring, v.traffic_sign_recognition, v.night_vision, v.adas_level,
-v.ncap_rating, v.ncap_body, v.ncap_year,
-v.seats, v.seat_material, v.driver_seat_electric, v.passenger_seat_electric, v.driver_seat_memory,
-v.ventilated_seats_front, v.ventilated_seats_rear, v.heated_seats_front, v.heated_seats_rear,
-v.rear_seat_recline, v.ac_zones, v.rear_ac_vents,
-v.screen_size_inch, v.screen_type, v.digital_cluster, v.cluster_size_inch, v.hud,
-v.speaker_brand, v.speaker_count, v.apple_carplay, v.android_auto,
-v.wireless_carplay, v.wireless_android_auto, v.wireless_phone_charging,
-v.usb_c_ports, v.usb_a_ports, v.bluetooth, v.ota_update,
-v.headlight_type, v.drl, v.auto_headlights, v.adaptive_headlights, v.fog_lights,
-v.sunroof, v.power_tailgate, v.hands_free_tailgate,
-v.keyless_entry, v.push_start, v.auto_folding_mirrors, v.rain_sensing_wipers, v.roof_rails,
-v.warranty_years, v.warranty_km, v.battery_warranty_years, v.battery_warranty_km`
-
-func scanVariant(scanner interface{ Scan(...interface{}) error }) (models.CarVariant, error) {
-	var v models.CarVariant
-	err := scanner.Scan(
-		&v.ID, &v.ModelID, &v.Name, &v.PriceBaht, &v.Status,
-		&v.BrandName, &v.ModelName, &v.PowertrainType, &v.BodyType,
-		&v.BatteryCapacityKwh, &v.BatteryType, &v.MotorPowerKw, &v.MotorTorqueNm,
-		&v.FrontMotorKw, &v.RearMotorKw, &v.RangeKm, &v.RangeStandard,
-		&v.AcChargeKw, &v.DcChargeKw, &v.AcChargeTimeHrs, &v.DcChargeTimeMins,
-		&v.ChargingPort, &v.V2l, &v.V2g, &v.HeatPump, &v.BatteryPreconditioning,
-		&v.DisplacementCc, &v.EngineType, &v.Horsepower, &v.EngineTorqueNm,
-		&v.FuelType, &v.FuelTankLiters, &v.FuelConsumptionKml, &v.Turbo,
-		&v.Transmission, &v.TransmissionSpeeds,
-		&v.SystemPowerHp, &v.SystemTorqueNm, &v.EvRangeKm,
-		&v.TopSpeedKmh, &v.Acceleration0100,
-		&v.LengthMm, &v.WidthMm, &v.HeightMm, &v.WheelbaseMm, &v.GroundClearanceMm,
-		&v.CurbWeightKg, &v.GrossWeightKg, &v.TrunkCapacityLiters, &v.TrunkMaxLiters, &v.FrunkCapacityLiters,
-		&v.DriveType, &v.FrontSuspension, &v.RearSuspension, &v.FrontBrakes, &v.RearBrakes,
-		&v.TireSizeFront, &v.TireSizeRear, &v.SpareTire,
-		&v.Airbags, &v.Abs, &v.Esc, &v.TractionControl, &v.HillStartAssist, &v.HillDescentControl,
-		&v.Tpms, &v.Isofix, &v.ParkingSensorFront, &v.ParkingSensorRear,
-		&v.CameraRear, &v.Camera360, &v.AutoParking,
-		&v.Aeb, &v.Fcw, &v.Lka, &v.Ldw, &v.Bsd, &v.Rcta, &v.Acc, &v.AccStopGo,
-		&v.DriverMonitoring, &v.TrafficSignRecognition, &v.NightVision, &v.AdasLevel,
-		&v.NcapRating, &v.NcapBody, &v.NcapYear,
-		&v.Seats, &v.SeatMaterial, &v.DriverSeatElectric, &v.PassengerSeatElectric, &v.DriverSeatMemory,
-		&v.VentilatedSeatsFront, &v.VentilatedSeatsRear, &v.HeatedSeatsFront, &v.HeatedSeatsRear,
-		&v.RearSeatRecline, &v.AcZones, &v.RearAcVents,
-		&v.ScreenSizeInch, &v.ScreenType, &v.DigitalCluster, &v.ClusterSizeInch, &v.Hud,
-		&v.SpeakerBrand, &v.SpeakerCount, &v.AppleCarplay, &v.AndroidAuto,
-		&v.WirelessCarplay, &v.WirelessAndroidAuto, &v.WirelessPhoneCharging,
-		&v.UsbCPorts, &v.UsbAPorts, &v.Bluetooth, &v.OtaUpdate,
-		&v.HeadlightType, &v.Drl, &v.AutoHeadlights, &v.AdaptiveHeadlights, &v.FogLights,
-		&v.Sunroof, &v.PowerTailgate, &v.HandsFreeTailgate,
-		&v.KeylessEntry, &v.PushStart, &v.AutoFoldingMirrors, &v.RainSensingWipers, &v.RoofRails,
-		&v.WarrantyYears, &v.WarrantyKm, &v.BatteryWarrantyYears, &v.BatteryWarrantyKm,
-	)
-	return v, err
+// GetSpecFields - GET /api/cars/spec-fields?section=battery
+func GetSpecFields(c *fiber.Ctx) error {
+	section := c.Query("section")
+
+	fields := []models.SpecField{}
+	for _, f := range models.SpecFields {
+		if section == "" || f.Section == section {
+			fields = append(fields, f)
+		}
+	}
+
+	if len(fields) == 0 {
+		return c.Status(404).JSON(fiber.Map{"error": "Section not found"})
+	}
+
+	return c.JSON(fiber.Map{
+		"sections": models.SpecSections,
+		"fields":   fields,
+	})
 }
 
-// GetCarVariantByID - GET /api/cars/variants/:id
+// GetCarVariantByID - GET /api/cars/variants/:id?fields=price_baht,range_km,safety.*
+// The gallery and colour options are always included.
 func GetCarVariantByID(c *fiber.Ctx) error {
 	id := c.Params("id")
 
-	query := "SELECT " + variantColumns + " FROM car_variants v JOIN car_models m ON v.model_id = m.id JOIN car_brands b ON m.brand_id = b.id WHERE v.id = ?"
+	cols, err := parseFieldSelection(c.Query("fields"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	query := "SELECT " + variantSelectList(cols) + " FROM car_variants v JOIN car_models m ON v.model_id = m.id JOIN car_brands b ON m.brand_id = b.id WHERE v.id = ?"
 
-	v, err := scanVariant(config.DB.QueryRow(query, id))
+	v, err := scanVariantColumns(config.DB.QueryRow(query, id), cols)
 	if err != nil {
 		return c.Status(404).JSON(fiber.Map{"error": "Variant not found"})
 	}
 
-	return c.JSON(v)
+	variants := []models.CarVariant{v}
+	markFavoriteVariants(c, variants)
+	detail, err := attachVariantMedia(variants[0])
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch images and colors"})
+	}
+
+	if cols != nil {
+		m := projectVariant(&variants[0], cols)
+		m["images"], m["colors"] = detail.Images, detail.Colors
+		return c.JSON(m)
+	}
+	return c.JSON(detail)
 }
 
-// CompareCarVariants - GET /api/cars/compare?ids=1,3,6
+// CompareCarVariants - GET /api/cars/compare?ids=1,3,6&mode=diff&fields=price_baht,adas.*
 func CompareCarVariants(c *fiber.Ctx) error {
 	idsParam := c.Query("ids")
 	if idsParam == "" {
 		return c.Status(400).JSON(fiber.Map{"error": "ids parameter is required (e.g. ?ids=1,3,6)"})
 	}
+	if mode := c.Query("mode"); mode != "" && mode != "full" && mode != "diff" {
+		return c.Status(400).JSON(fiber.Map{"error": "mode must be full or diff"})
+	}
 
-	ids := strings.Split(idsParam, ",")
-	if len(ids) < 2 || len(ids) > 4 {
+	ids, err := parseIDList(idsParam)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "ids must be a comma separated list of variant IDs"})
+	}
+	if len(ids) < minCompareVariants || len(ids) > maxCompareVariants {
 		return c.Status(400).JSON(fiber.Map{"error": "Compare 2-4 variants (e.g. ?ids=1,3)"})
 	}
 
+	cols, err := parseFieldSelection(c.Query("fields"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	variants, err := fetchVariantsByIDs(ids, compareColumns(c, cols))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch variants for comparison"})
+	}
+
+	if len(variants) == 0 {
+		return c.Status(404).JSON(fiber.Map{"error": "No variants found"})
+	}
+	markFavoriteVariants(c, variants)
+	markPriceChanges(variants)
+
+	return c.JSON(comparePayload(c, variants, cols))
+}
+
+const (
+	minCompareVariants = 2
+	maxCompareVariants = 4
+)
+
+// comparePayload - response body shared by every endpoint that returns a comparison.
+// ?mode=diff switches to the grouped spec-diff layout; cols is the ?fields= selection.
+func comparePayload(c *fiber.Ctx, variants []models.CarVariant, cols []variantColumn) fiber.Map {
+	if c.Query("mode") == "diff" {
+		return compareDiffPayload(variants, c.QueryBool("hide_identical"), cols)
+	}
+	return fiber.Map{
+		"count":    len(variants),
+		"variants": projectVariants(variants, cols),
+	}
+}
+
+// compareColumns - what to select for a comparison: diff mode always needs the
+// identity fields for its variant headers
+func compareColumns(c *fiber.Ctx, cols []variantColumn) []variantColumn {
+	if c.Query("mode") == "diff" {
+		return withColumns(cols, "name", "brand_name", "model_name", "powertrain_type", "body_type", "price_baht", "status")
+	}
+	return cols
+}
+
+// fetchVariantsByIDs - specs for the given variants, in the order the IDs were given.
+// cols is a field selection (nil = full spec). IDs that do not exist are skipped.
+func fetchVariantsByIDs(ids []int, cols []variantColumn) ([]models.CarVariant, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
 	placeholders := strings.Repeat("?,", len(ids))
 	placeholders = placeholders[:len(placeholders)-1]
 
-	query := "SELECT " + variantColumns + " FROM car_variants v JOIN car_models m ON v.model_id = m.id JOIN car_brands b ON m.brand_id = b.id WHERE v.id IN (" + placeholders + ")"
+	query := "SELECT " + variantSelectList(cols) + " FROM car_variants v JOIN car_models m ON v.model_id = m.id JOIN car_brands b ON m.brand_id = b.id WHERE v.id IN (" + placeholders + ")"
 
 	args := make([]interface{}, len(ids))
 	for i, id := range ids {
-		args[i] = strings.TrimSpace(id)
+		args[i] = id
 	}
 
 	rows, err := config.DB.Query(query, args...)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch variants for comparison"})
+		return nil, err
 	}
 	defer rows.Close()
 
-	var variants []models.CarVariant
+	byID := map[int]models.CarVariant{}
 	for rows.Next() {
-		v, err := scanVariant(rows)
+		v, err := scanVariantColumns(rows, cols)
 		if err != nil {
 			continue
 		}
-		variants = append(variants, v)
+		byID[v.ID] = v
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
-	if len(variants) == 0 {
-		return c.Status(404).JSON(fiber.Map{"error": "No variants found"})
+	var variants []models.CarVariant
+	for _, id := range ids {
+		if v, ok := byID[id]; ok {
+			variants = append(variants, v)
+			delete(byID, id)
+		}
 	}
+	return variants, nil
+}
 
-	return c.JSON(fiber.Map{
-		"count":    len(variants),
-		"variants": variants,
-	})
+// parseIDList - "1, 3,6" -> [1 3 6]
+func parseIDList(s string) ([]int, error) {
+	parts := strings.Split(s, ",")
+	ids := make([]int, 0, len(parts))
+	for _, p := range parts {
+		id, err := strconv.Atoi(strings.TrimSpace(p))
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid id %q", p)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
 }
 
-// BrowseCarVariants - GET /api/cars/browse?min_price=500000&max_price=1500000&powertrain_type=BEV
-func BrowseCarVariants(c *fiber.Ctx) error {
-	minPrice := c.Query("min_price")
-	maxPrice := c.Query("max_price")
-	powertrainType := c.Query("powertrain_type")
+// variantSearchResult - one row of the browse and search lists
+type variantSearchResult struct {
+	VariantID          int      `json:"variant_id"`
+	ModelID            int      `json:"model_id"`
+	VariantName        string   `json:"variant_name"`
+	PriceBaht          *float64 `json:"price_baht"`
+	Status             string   `json:"status"`
+	BrandName          string   `json:"brand_name"`
+	ModelName          string   `json:"model_name"`
+	PowertrainType     string   `json:"powertrain_type"`
+	RangeKm            *int     `json:"range_km"`
+	FuelConsumptionKml *float64 `json:"fuel_consumption_kml"`
+	// Score - search relevance, only set when sorting by relevance
+	Score       *float64            `json:"score,omitempty"`
+	PriceChange *models.PriceChange `json:"price_change,omitempty"`
+}
 
-	minRange := c.Query("min_range")
-	minFuelEfficiency := c.Query("min_fuel_efficiency")
+const variantSearchColumns = "SELECT v.id, v.model_id, v.name, v.price_baht, v.status, b.name, m.name, m.powertrain_type, v.range_km, v.fuel_consumption_kml"
 
-	query := `SELECT v.id, v.model_id, v.name, v.price_baht, v.status, b.name, m.name, m.powertrain_type, v.range_km, v.fuel_consumption_kml
+const variantSearchFrom = `
 		FROM car_variants v
 		JOIN car_models m ON v.model_id = m.id
-		JOIN car_brands b ON m.brand_id = b.id
-		WHERE v.price_baht IS NOT NULL`
-	args := []interface{}{}
+		JOIN car_brands b ON m.brand_id = b.id`
 
-	if minPrice != "" {
-		query += " AND v.price_baht >= ?"
-		args = append(args, minPrice)
-	}
-	if maxPrice != "" {
-		query += " AND v.price_baht <= ?"
-		args = append(args, maxPrice)
-	}
-	if powertrainType != "" {
-		query += " AND m.powertrain_type = ?"
-		args = append(args, powertrainType)
-	}
-	if minRange != "" {
-		query += " AND v.range_km >= ?"
-		args = append(args, minRange)
-	}
-	if minFuelEfficiency != "" {
-		query += " AND v.fuel_consumption_kml >= ?"
-		args = append(args, minFuelEfficiency)
+// fetchVariantList - runs a paginated variant list query and returns the envelope
+func fetchVariantList(list listQuery, from string, args []interface{}) (fiber.Map, error) {
+	total, err := countRows(from, args)
+	if err != nil {
+		return nil, err
 	}
 
-	query += " ORDER BY v.price_baht LIMIT 50"
-
-	rows, err := config.DB.Query(query, args...)
+	page, pageArgs := list.page()
+	rows, err := config.DB.Query(variantSearchColumns+list.keyColumns()+from+page, append(args, pageArgs...)...)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Browse failed"})
+		return nil, err
 	}
 	defer rows.Close()
 
-	type SearchResult struct {
-		VariantID          int      `json:"variant_id"`
-		ModelID            int      `json:"model_id"`
-		VariantName        string   `json:"variant_name"`
-		PriceBaht          *float64 `json:"price_baht"`
-		Status             string   `json:"status"`
-		BrandName          string   `json:"brand_name"`
-		ModelName          string   `json:"model_name"`
-		PowertrainType     string   `json:"powertrain_type"`
-		RangeKm            *int     `json:"range_km"`
-		FuelConsumptionKml *float64 `json:"fuel_consumption_kml"`
-	}
-
-	var results []SearchResult
+	results := []variantSearchResult{}
 	for rows.Next() {
-		var r SearchResult
-		rows.Scan(&r.VariantID, &r.ModelID, &r.VariantName, &r.PriceBaht, &r.Status, &r.BrandName, &r.ModelName, &r.PowertrainType, &r.RangeKm, &r.FuelConsumptionKml)
+		var r variantSearchResult
+		rows.Scan(append([]interface{}{&r.VariantID, &r.ModelID, &r.VariantName, &r.PriceBaht, &r.Status, &r.BrandName, &r.ModelName, &r.PowertrainType, &r.RangeKm, &r.FuelConsumptionKml}, list.scanKey()...)...)
 		results = append(results, r)
 	}
+	markPriceChangeResults(results)
 
-	return c.JSON(results)
+	return listEnvelope(list, results, total), nil
 }
 
-// SearchCars - GET /api/cars/search?q=atto
-func SearchCars(c *fiber.Ctx) error {
-	q := c.Query("q")
-	if q == "" {
-		return c.Status(400).JSON(fiber.Map{"error": "q parameter is required"})
-	}
+// browseReservedParams - browse query parameters that are not filters
+var browseReservedParams = []string{"sort", "limit", "cursor", "facets"}
 
-	query := `SELECT v.id, v.model_id, v.name, v.price_baht, v.status, b.name, m.name, m.powertrain_type
-		FROM car_variants v
-		JOIN car_models m ON v.model_id = m.id
-		JOIN car_brands b ON m.brand_id = b.id
-		WHERE b.name LIKE ? OR m.name LIKE ? OR v.name LIKE ?
-		ORDER BY b.name, m.name, v.price_baht
-		LIMIT 20`
+// BrowseCarVariants - GET /api/cars/browse?min_price=500000&max_price=1500000&powertrain_type=BEV&sort=-range&limit=&cursor=
+// Any spec field can be filtered on, see variant_filter.go, e.g.
+// ?drive_type=AWD&min_horsepower=300&v2l=true&body_type=suv,crossover&seats=7
+// Facet counts are included unless facets=false.
+func BrowseCarVariants(c *fiber.Ctx) error {
+	list, err := parseListQuery(c, variantSorts, "v.id", "price", 50)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
 
-	searchTerm := "%" + q + "%"
-	rows, err := config.DB.Query(query, searchTerm, searchTerm, searchTerm)
+	params := c.Queries()
+	filter, err := parseVariantFilters(params, browseReservedParams...)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Search failed"})
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
 	}
-	defer rows.Close()
 
-	type SearchResult struct {
-		VariantID      int      `json:"variant_id"`
-		ModelID        int      `json:"model_id"`
-		VariantName    string   `json:"variant_name"`
-		PriceBaht      *float64 `json:"price_baht"`
-		Status         string   `json:"status"`
-		BrandName      string   `json:"brand_name"`
-		ModelName      string   `json:"model_name"`
-		PowertrainType string   `json:"powertrain_type"`
+	base := variantSearchFrom + `
+		WHERE v.price_baht IS NOT NULL`
+
+	result, err := fetchVariantList(list, base+filter.SQL(), filter.Args)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Browse failed"})
 	}
+	result["filters"] = filter.Applied
 
-	var results []SearchResult
-	for rows.Next() {
-		var r SearchResult
-		rows.Scan(&r.VariantID, &r.ModelID, &r.VariantName, &r.PriceBaht, &r.Status, &r.BrandName, &r.ModelName, &r.PowertrainType)
-		results = append(results, r)
+	if c.Query("facets") != "false" {
+		facets, err := browseFacets(params, base, browseReservedParams)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Browse failed"})
+		}
+		result["facets"] = facets
 	}
 
-	return c.JSON(results)
+	return c.JSON(result)
 }
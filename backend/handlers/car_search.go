@@ -0,0 +1,328 @@
+package handlers
+
+import (
+	"comparebuddy-backend/config"
+	"comparebuddy-backend/search"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// carSearchIndex - every variant, searchable by brand (English and Thai),
+// model and variant name. Rebuilt from the database every few minutes.
+var carSearchIndex = search.NewIndex(5*time.Minute, loadCarSearchDocuments)
+
+// carSuggestIndex - brands, models and variants for autocomplete
+var carSuggestIndex = search.NewIndex(5*time.Minute, loadCarSuggestDocuments)
+
+const (
+	defaultSuggestLimit = 8
+	maxSuggestLimit     = 20
+)
+
+// searchSorts - variantSorts plus relevance, the default for search
+var searchSorts = func() map[string][]string {
+	// relevance is ranked by the index, not SQL; its cursor carries the score
+	sorts := map[string][]string{"relevance": {"score"}}
+	for k, v := range variantSorts {
+		sorts[k] = v
+	}
+	return sorts
+}()
+
+func loadCarSearchDocuments() ([]search.Document, error) {
+	rows, err := config.DB.Query(`SELECT v.id, b.name, COALESCE(b.name_th, ''), m.name, v.name
+		FROM car_variants v
+		JOIN car_models m ON v.model_id = m.id
+		JOIN car_brands b ON m.brand_id = b.id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var docs []search.Document
+	for rows.Next() {
+		var id int
+		var brand, brandTh, model, variant string
+		if err := rows.Scan(&id, &brand, &brandTh, &model, &variant); err != nil {
+			return nil, err
+		}
+		// Brand, model, variant first so "byd atto 3" reads as one phrase
+		docs = append(docs, search.Document{ID: id, Fields: []search.Field{
+			{Text: brand, Weight: search.WeightBrand},
+			{Text: model, Weight: search.WeightModel},
+			{Text: variant, Weight: search.WeightVariant},
+			{Text: brandTh, Weight: search.WeightBrand},
+		}})
+	}
+	return docs, rows.Err()
+}
+
+func loadCarSuggestDocuments() ([]search.Document, error) {
+	var docs []search.Document
+
+	rows, err := config.DB.Query("SELECT id, name, COALESCE(name_th, '') FROM car_brands")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int
+		var name, nameTh string
+		if err := rows.Scan(&id, &name, &nameTh); err != nil {
+			return nil, err
+		}
+		docs = append(docs, search.Document{Kind: "brand", ID: id, Label: name, Fields: []search.Field{
+			{Text: name, Weight: search.WeightBrand},
+			{Text: nameTh, Weight: search.WeightBrand},
+		}})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	modelRows, err := config.DB.Query("SELECT m.id, b.name, m.name FROM car_models m JOIN car_brands b ON m.brand_id = b.id")
+	if err != nil {
+		return nil, err
+	}
+	defer modelRows.Close()
+	for modelRows.Next() {
+		var id int
+		var brand, model string
+		if err := modelRows.Scan(&id, &brand, &model); err != nil {
+			return nil, err
+		}
+		docs = append(docs, search.Document{Kind: "model", ID: id, Label: brand + " " + model, Fields: []search.Field{
+			{Text: brand, Weight: search.WeightBrand},
+			{Text: model, Weight: search.WeightModel},
+		}})
+	}
+	if err := modelRows.Err(); err != nil {
+		return nil, err
+	}
+
+	variants, err := loadCarSearchDocuments()
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range variants {
+		d.Kind = "variant"
+		d.Label = d.Fields[0].Text + " " + d.Fields[1].Text + " " + d.Fields[2].Text
+		docs = append(docs, d)
+	}
+	return docs, nil
+}
+
+// SuggestCars - GET /api/cars/suggest?q=tesal&limit=8
+// Typed autocomplete suggestions (brand, model, variant) for the search box,
+// tolerant of one or two typos per word.
+func SuggestCars(c *fiber.Ctx) error {
+	limit := defaultSuggestLimit
+	if s := c.Query("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > maxSuggestLimit {
+			return c.Status(400).JSON(fiber.Map{"error": fmt.Sprintf("limit must be between 1 and %d", maxSuggestLimit)})
+		}
+		limit = n
+	}
+
+	suggestions, err := carSuggestIndex.Suggest(c.Query("q"), limit)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Suggest failed"})
+	}
+
+	return c.JSON(fiber.Map{
+		"query":       c.Query("q"),
+		"suggestions": suggestions,
+	})
+}
+
+// SearchCars - GET /api/cars/search?q=atto&sort=&limit=&cursor=
+// Matches English and Thai brand names, model and variant names regardless of
+// case, spacing and punctuation ("byd atto3" finds "BYD ATTO 3").
+// Price bounds, powertrain, body type, seats and range written in the query
+// ("EV SUV under 1.5 million", "รถไฟฟ้า ไม่เกิน 1 ล้าน") become browse filters,
+// echoed back as "interpreted". Results are ranked by relevance unless another
+// sort is given; a query that is only filters is sorted by price, and cannot be
+// sorted by relevance.
+func SearchCars(c *fiber.Ctx) error {
+	q := c.Query("q")
+	if q == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "q parameter is required"})
+	}
+
+	interp := search.ParseQuery(q)
+	defaultSort := "relevance"
+	if interp.Text == "" {
+		defaultSort = "price"
+	}
+	list, err := parseListQuery(c, searchSorts, "v.id", defaultSort, 20)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if interp.Text == "" && list.SortKey == "relevance" {
+		return c.Status(400).JSON(fiber.Map{"error": "sort=relevance needs words to match; this query only has filters"})
+	}
+
+	filter, err := parseVariantFilters(interp.Filters)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	interpreted := fiber.Map{
+		"filters": filter.Applied,
+		"text":    interp.Text,
+		"matched": interp.Matched,
+	}
+
+	// Nothing left to match by name: a plain filtered listing
+	if interp.Text == "" {
+		result, err := fetchVariantList(list, variantSearchFrom+`
+		WHERE 1=1`+filter.SQL(), filter.Args)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Search failed"})
+		}
+		result["interpreted"] = interpreted
+		return c.JSON(result)
+	}
+
+	hits, err := carSearchIndex.Search(interp.Text)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Search failed"})
+	}
+	if len(filter.Conditions) > 0 && len(hits) > 0 {
+		if hits, err = filterHits(hits, filter); err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Search failed"})
+		}
+	}
+	if len(hits) == 0 {
+		result := listEnvelope(list, []variantSearchResult{}, 0)
+		result["interpreted"] = interpreted
+		return c.JSON(result)
+	}
+
+	if list.SortKey != "relevance" {
+		args := make([]interface{}, len(hits))
+		for i, h := range hits {
+			args[i] = h.ID
+		}
+		from := variantSearchFrom + `
+		WHERE v.id IN (` + sqlPlaceholders(len(hits)) + `)`
+
+		result, err := fetchVariantList(list, from, args)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Search failed"})
+		}
+		result["interpreted"] = interpreted
+		return c.JSON(result)
+	}
+
+	// Relevance order lives in the index, so the page is cut here
+	start := 0
+	if list.After != nil {
+		start = len(hits)
+		for i, h := range hits {
+			if hitAfter(h, list.After) {
+				start = i
+				break
+			}
+		}
+	}
+	end := start + list.Limit + 1
+	if end > len(hits) {
+		end = len(hits)
+	}
+
+	results, err := fetchVariantResultsByIDs(hits[start:end])
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Search failed"})
+	}
+	for _, r := range results {
+		list.addKey(*r.Score, r.VariantID)
+	}
+
+	result := listEnvelope(list, results, len(hits))
+	result["interpreted"] = interpreted
+	return c.JSON(result)
+}
+
+// hitAfter - whether h ranks after the [score, id] key of a relevance cursor
+// (score descending, then id)
+func hitAfter(h search.Hit, after []interface{}) bool {
+	score, _ := after[0].(float64)
+	id, _ := after[1].(float64)
+	return h.Score < score || h.Score == score && float64(h.ID) > id
+}
+
+// filterHits - the hits that also pass filter, keeping their order
+func filterHits(hits []search.Hit, filter variantFilter) ([]search.Hit, error) {
+	args := make([]interface{}, 0, len(hits)+len(filter.Args))
+	for _, h := range hits {
+		args = append(args, h.ID)
+	}
+	args = append(args, filter.Args...)
+
+	rows, err := config.DB.Query("SELECT v.id"+variantSearchFrom+`
+		WHERE v.id IN (`+sqlPlaceholders(len(hits))+`)`+filter.SQL(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keep := map[int]bool{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		keep[id] = true
+	}
+
+	out := []search.Hit{}
+	for _, h := range hits {
+		if keep[h.ID] {
+			out = append(out, h)
+		}
+	}
+	return out, rows.Err()
+}
+
+// fetchVariantResultsByIDs - list rows for the given hits, in hit order with scores
+func fetchVariantResultsByIDs(hits []search.Hit) ([]variantSearchResult, error) {
+	results := []variantSearchResult{}
+	if len(hits) == 0 {
+		return results, nil
+	}
+
+	args := make([]interface{}, len(hits))
+	for i, h := range hits {
+		args[i] = h.ID
+	}
+
+	rows, err := config.DB.Query(variantSearchColumns+variantSearchFrom+`
+		WHERE v.id IN (`+sqlPlaceholders(len(hits))+`)`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byID := map[int]variantSearchResult{}
+	for rows.Next() {
+		var r variantSearchResult
+		rows.Scan(&r.VariantID, &r.ModelID, &r.VariantName, &r.PriceBaht, &r.Status, &r.BrandName, &r.ModelName, &r.PowertrainType, &r.RangeKm, &r.FuelConsumptionKml)
+		byID[r.VariantID] = r
+	}
+
+	for _, h := range hits {
+		r, ok := byID[h.ID]
+		if !ok {
+			continue
+		}
+		score := h.Score
+		r.Score = &score
+		results = append(results, r)
+	}
+	markPriceChangeResults(results)
+	return results, rows.Err()
+}
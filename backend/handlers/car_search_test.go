@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestSuggestCarsRejectsBadLimit(t *testing.T) {
+	app := fiber.New()
+	app.Get("/suggest", SuggestCars)
+
+	for _, limit := range []string{"abc", "0", "21", "2.5"} {
+		resp, err := app.Test(httptest.NewRequest("GET", "/suggest?q=tes&limit="+limit, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != 400 {
+			t.Errorf("limit=%s: status %d, want 400", limit, resp.StatusCode)
+		}
+	}
+}
+
+func TestSearchCarsFilterOnlySort(t *testing.T) {
+	app := fiber.New()
+	app.Get("/search", SearchCars)
+
+	// "EV SUV under 1.5 million" leaves no text to rank by relevance
+	relevanceCursor := encodeCursor(listCursor{SortKey: "relevance", After: []interface{}{1.0, 5.0}})
+	for _, query := range []string{"sort=relevance", "sort=-relevance", "cursor=" + relevanceCursor} {
+		resp, err := app.Test(httptest.NewRequest("GET", "/search?q=EV+SUV+under+1.5+million&"+query, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != 400 {
+			t.Errorf("%s: status %d, want 400", query, resp.StatusCode)
+		}
+	}
+}
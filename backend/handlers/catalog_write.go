@@ -0,0 +1,316 @@
+package handlers
+
+import (
+	"bytes"
+	"comparebuddy-backend/audit"
+	"comparebuddy-backend/config"
+	"comparebuddy-backend/models"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// writableColumn - one column an admin write may set
+type writableColumn struct {
+	Key     string // json key in request bodies
+	Column  string
+	Type    string // models.SpecType*
+	Options []string
+	// Required - NOT NULL without a default: needed on create and replace
+	Required bool
+	// References - table the value must be an id of
+	References string
+}
+
+// catalogTable - a catalog table editable through the admin API
+type catalogTable struct {
+	Table   string
+	Label   string // for messages: "Brand not found"
+	Columns []writableColumn
+	// Searchable - rows feed the car search and suggestion indexes and the
+	// similar-variants catalog, which are rebuilt after a write
+	Searchable bool
+}
+
+func (t catalogTable) noun() string {
+	return strings.ToLower(t.Label)
+}
+
+// Write modes of catalogTable.parseWrite
+const (
+	writeCreate  = iota // POST: required columns must be set, others keep their default
+	writeReplace        // PUT: like create, and every column left out is reset to its default
+	writeUpdate         // PATCH: only the given columns change
+)
+
+// columnValue - a validated value; Default means SET column = DEFAULT
+type columnValue struct {
+	Column  string
+	Value   interface{}
+	Default bool
+}
+
+var brandTable = catalogTable{
+	Table:      "car_brands",
+	Label:      "Brand",
+	Searchable: true,
+	Columns: []writableColumn{
+		{Key: "name", Column: "name", Type: models.SpecTypeText, Required: true},
+		{Key: "name_th", Column: "name_th", Type: models.SpecTypeText},
+		{Key: "country", Column: "country", Type: models.SpecTypeText},
+		{Key: "logo_url", Column: "logo_url", Type: models.SpecTypeText},
+		{Key: "status", Column: "status", Type: models.SpecTypeEnum, Options: models.BrandStatuses},
+	},
+}
+
+var modelTable = catalogTable{
+	Table:      "car_models",
+	Label:      "Model",
+	Searchable: true,
+	Columns: []writableColumn{
+		{Key: "brand_id", Column: "brand_id", Type: models.SpecTypeInteger, Required: true, References: "car_brands"},
+		{Key: "name", Column: "name", Type: models.SpecTypeText, Required: true},
+		{Key: "powertrain_type", Column: "powertrain_type", Type: models.SpecTypeEnum, Options: models.PowertrainTypes, Required: true},
+		{Key: "body_type", Column: "body_type", Type: models.SpecTypeEnum, Options: models.BodyTypes, Required: true},
+		{Key: "segment", Column: "segment", Type: models.SpecTypeEnum, Options: models.Segments, Required: true},
+		{Key: "year_launched", Column: "year_launched", Type: models.SpecTypeInteger},
+		{Key: "status", Column: "status", Type: models.SpecTypeEnum, Options: models.CarStatuses},
+	},
+}
+
+var itemTable = catalogTable{
+	Table: "items",
+	Label: "Item",
+	Columns: []writableColumn{
+		{Key: "category_id", Column: "category_id", Type: models.SpecTypeInteger, Required: true, References: "categories"},
+		{Key: "brand", Column: "brand", Type: models.SpecTypeText, Required: true},
+		{Key: "name", Column: "name", Type: models.SpecTypeText, Required: true},
+		{Key: "duration", Column: "duration", Type: models.SpecTypeText},
+		{Key: "price", Column: "price", Type: models.SpecTypeDecimal, Required: true},
+		{Key: "field", Column: "field", Type: models.SpecTypeText},
+	},
+}
+
+var categoryTable = catalogTable{
+	Table: "categories",
+	Label: "Category",
+	Columns: []writableColumn{
+		{Key: "main_category_id", Column: "main_category_id", Type: models.SpecTypeInteger, Required: true, References: "main_categories"},
+		{Key: "name", Column: "name", Type: models.SpecTypeText, Required: true},
+		{Key: "name_en", Column: "name_en", Type: models.SpecTypeText},
+	},
+}
+
+// variantTable - every car_variants column of the spec registry; built in
+// init() of variant_columns.go
+var variantTable catalogTable
+
+func variantCatalogTable() catalogTable {
+	t := catalogTable{Table: "car_variants", Label: "Variant", Searchable: true}
+	for _, col := range variantColumnList {
+		if col.Table != "car_variants" || col.Key == "id" {
+			continue
+		}
+		spec := models.SpecFieldByKey[col.Key]
+		wc := writableColumn{Key: col.Key, Column: col.Name, Type: spec.Type, Options: spec.Options}
+		switch col.Key {
+		case "model_id":
+			wc.Required, wc.References = true, "car_models"
+		case "name":
+			wc.Required = true
+		}
+		t.Columns = append(t.Columns, wc)
+	}
+	return t
+}
+
+// parseWrite - validates a JSON object body against the table's columns.
+// Unknown keys, wrong types, values outside an ENUM and missing required
+// columns are errors meant for the client.
+func (t catalogTable) parseWrite(body []byte, mode int) ([]columnValue, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil || raw == nil {
+		return nil, errors.New("Invalid request body")
+	}
+
+	known := map[string]bool{}
+	var values []columnValue
+	for _, col := range t.Columns {
+		known[col.Key] = true
+		msg, ok := raw[col.Key]
+		if !ok {
+			if mode != writeUpdate && col.Required {
+				return nil, fmt.Errorf("%s is required", col.Key)
+			}
+			if mode == writeReplace {
+				values = append(values, columnValue{Column: col.Column, Default: true})
+			}
+			continue
+		}
+		v, err := col.parse(msg)
+		if err != nil {
+			return nil, err
+		}
+		if v == nil && col.Required {
+			return nil, fmt.Errorf("%s cannot be null", col.Key)
+		}
+		if v != nil && col.References != "" {
+			if err := checkReference(col, v); err != nil {
+				return nil, err
+			}
+		}
+		values = append(values, columnValue{Column: col.Column, Value: v})
+	}
+
+	var unknown []string
+	for key := range raw {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unknown field %q", unknown[0])
+	}
+	if len(values) == 0 {
+		return nil, errors.New("no fields to update")
+	}
+	return values, nil
+}
+
+// parse - the value to store, or nil for JSON null
+func (col writableColumn) parse(msg json.RawMessage) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(msg))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("%s: invalid value", col.Key)
+	}
+	if v == nil {
+		return nil, nil
+	}
+
+	switch col.Type {
+	case models.SpecTypeInteger:
+		n, ok := v.(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("%s must be an integer", col.Key)
+		}
+		i, err := n.Int64()
+		if err != nil || i < 0 {
+			return nil, fmt.Errorf("%s must be a non-negative integer", col.Key)
+		}
+		return i, nil
+	case models.SpecTypeDecimal:
+		n, ok := v.(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a number", col.Key)
+		}
+		f, err := n.Float64()
+		if err != nil || f < 0 {
+			return nil, fmt.Errorf("%s must be a non-negative number", col.Key)
+		}
+		return f, nil
+	case models.SpecTypeBoolean:
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s must be true or false", col.Key)
+		}
+		return b, nil
+	case models.SpecTypeEnum:
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be one of: %s", col.Key, strings.Join(col.Options, ", "))
+		}
+		values, err := enumValues(col.Key, s, col.Options)
+		if err != nil || len(values) != 1 {
+			return nil, fmt.Errorf("%s must be one of: %s", col.Key, strings.Join(col.Options, ", "))
+		}
+		return values[0], nil
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		return nil, fmt.Errorf("%s must be a string", col.Key)
+	}
+	s = strings.TrimSpace(s)
+	if s == "" {
+		if col.Required {
+			return nil, fmt.Errorf("%s cannot be empty", col.Key)
+		}
+		return nil, nil
+	}
+	return s, nil
+}
+
+func checkReference(col writableColumn, id interface{}) error {
+	var exists int
+	err := config.DB.QueryRow("SELECT 1 FROM "+col.References+" WHERE id = ?", id).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%s %v does not exist", col.Key, id)
+	}
+	return err
+}
+
+// insertRow - INSERT of the parsed values, returns the new id
+func (t catalogTable) insertRow(q audit.Querier, values []columnValue) (int64, error) {
+	cols := make([]string, len(values))
+	args := make([]interface{}, len(values))
+	for i, v := range values {
+		cols[i] = v.Column
+		args[i] = v.Value
+	}
+	result, err := q.Exec(
+		"INSERT INTO "+t.Table+" ("+strings.Join(cols, ", ")+") VALUES ("+sqlPlaceholders(len(values))+")",
+		args...,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// updateRow - UPDATE of the parsed values
+func (t catalogTable) updateRow(q audit.Querier, id int, values []columnValue) error {
+	sets := make([]string, len(values))
+	args := make([]interface{}, 0, len(values)+1)
+	for i, v := range values {
+		if v.Default {
+			sets[i] = v.Column + " = DEFAULT"
+			continue
+		}
+		sets[i] = v.Column + " = ?"
+		args = append(args, v.Value)
+	}
+	_, err := q.Exec("UPDATE "+t.Table+" SET "+strings.Join(sets, ", ")+" WHERE id = ?", append(args, id)...)
+	return err
+}
+
+// written - after a committed write
+func (t catalogTable) written() {
+	if t.Searchable {
+		carSearchIndex.Invalidate()
+		carSuggestIndex.Invalidate()
+		similarCatalog.Invalidate()
+	}
+}
+
+// clientWriteError - MySQL errors caused by the submitted data (too long,
+// out of range) as a message for a 400; "" for server-side failures
+func clientWriteError(err error) string {
+	var me *mysql.MySQLError
+	if !errors.As(err, &me) {
+		return ""
+	}
+	switch me.Number {
+	case 1264, 1364, 1366, 1406:
+		// out of range, missing value, incorrect value, data too long
+		return me.Message
+	}
+	return ""
+}
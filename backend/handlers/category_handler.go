@@ -26,7 +26,7 @@ func GetMainCategories(c *fiber.Ctx) error {
 func GetSubCategories(c *fiber.Ctx) error {
 	mainCategoryID := c.Query("main_category_id")
 	
-	query := "SELECT id, main_category_id, name, name_en FROM categories"
+	query := "SELECT id, main_category_id, name, COALESCE(name_en, '') FROM categories"
 	args := []interface{}{}
 	
 	if mainCategoryID != "" {
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"comparebuddy-backend/models"
+	"reflect"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Row status in diff mode
+const (
+	rowIdentical = "identical"
+	rowDifferent = "different"
+	rowMissing   = "missing"
+)
+
+type diffRow struct {
+	Key     string        `json:"key"`
+	LabelTh string        `json:"label_th"`
+	LabelEn string        `json:"label_en"`
+	Unit    string        `json:"unit,omitempty"`
+	Status  string        `json:"status"`
+	Compare string        `json:"compare"`
+	Values  []interface{} `json:"values"`
+	// IDs of the variants holding the best value; empty when the row has no winner
+	Best []int `json:"best"`
+}
+
+type diffSection struct {
+	Key     string    `json:"key"`
+	LabelTh string    `json:"label_th"`
+	LabelEn string    `json:"label_en"`
+	Rows    []diffRow `json:"rows"`
+}
+
+// variantFieldValue - the value of a spec field by json key; nil when unset
+func variantFieldValue(v *models.CarVariant, key string) interface{} {
+	i, ok := variantFieldIndex[key]
+	if !ok {
+		return nil
+	}
+	f := reflect.ValueOf(v).Elem().Field(i)
+	if f.Kind() == reflect.Ptr {
+		if f.IsNil() {
+			return nil
+		}
+		f = f.Elem()
+	}
+	return f.Interface()
+}
+
+// compareDiffPayload - ?mode=diff body: one row per spec field with the
+// values side by side, a status and the winning variants.
+// With hide_identical=true rows where every variant agrees, or where no
+// variant has a value at all, are left out.
+// cols limits the rows to a ?fields= selection (nil = every field).
+func compareDiffPayload(variants []models.CarVariant, hideIdentical bool, cols []variantColumn) fiber.Map {
+	headers := make([]fiber.Map, len(variants))
+	for i, v := range variants {
+		headers[i] = fiber.Map{
+			"id":              v.ID,
+			"name":            v.Name,
+			"brand_name":      v.BrandName,
+			"model_name":      v.ModelName,
+			"powertrain_type": v.PowertrainType,
+			"body_type":       v.BodyType,
+			"price_baht":      v.PriceBaht,
+			"status":          v.Status,
+			"favorited":       v.Favorited,
+			"price_change":    v.PriceChange,
+		}
+	}
+
+	bySection := map[string][]models.SpecField{}
+	for _, f := range models.SpecFields {
+		if selectionHas(cols, f.Key) {
+			bySection[f.Section] = append(bySection[f.Section], f)
+		}
+	}
+
+	summary := map[string]int{rowIdentical: 0, rowDifferent: 0, rowMissing: 0}
+	sections := []diffSection{}
+	for _, sec := range models.SpecSections {
+		// Identity fields are already in the variant headers
+		if sec.Key == "identity" {
+			continue
+		}
+		out := diffSection{Key: sec.Key, LabelTh: sec.LabelTh, LabelEn: sec.LabelEn, Rows: []diffRow{}}
+		for _, field := range bySection[sec.Key] {
+			row := buildDiffRow(variants, field)
+			summary[row.Status]++
+			if hideIdentical && (row.Status == rowIdentical || allNil(row.Values)) {
+				continue
+			}
+			out.Rows = append(out.Rows, row)
+		}
+		if len(out.Rows) > 0 {
+			sections = append(sections, out)
+		}
+	}
+
+	return fiber.Map{
+		"mode":     "diff",
+		"count":    len(variants),
+		"variants": headers,
+		"sections": sections,
+		"summary":  summary,
+	}
+}
+
+func buildDiffRow(variants []models.CarVariant, field models.SpecField) diffRow {
+	row := diffRow{
+		Key:     field.Key,
+		LabelTh: field.LabelTh,
+		LabelEn: field.LabelEn,
+		Unit:    field.Unit,
+		Compare: field.Compare,
+		Values:  make([]interface{}, len(variants)),
+		Best:    []int{},
+	}
+
+	present := 0
+	distinct := map[interface{}]bool{}
+	for i := range variants {
+		val := variantFieldValue(&variants[i], field.Key)
+		row.Values[i] = val
+		if val != nil {
+			present++
+			distinct[val] = true
+		}
+	}
+
+	switch {
+	case present < len(variants):
+		row.Status = rowMissing
+	case len(distinct) <= 1:
+		row.Status = rowIdentical
+	default:
+		row.Status = rowDifferent
+	}
+
+	if field.Compare == models.CompareNone || len(distinct) < 2 {
+		return row
+	}
+
+	var best float64
+	for i, val := range row.Values {
+		score, ok := comparableValue(val)
+		if !ok {
+			continue
+		}
+		if field.Compare == models.CompareLower {
+			score = -score
+		}
+		switch {
+		case len(row.Best) == 0 || score > best:
+			best = score
+			row.Best = []int{variants[i].ID}
+		case score == best:
+			row.Best = append(row.Best, variants[i].ID)
+		}
+	}
+	return row
+}
+
+func allNil(values []interface{}) bool {
+	for _, v := range values {
+		if v != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// comparableValue maps numbers and booleans (true = 1) onto a float for ranking
+func comparableValue(val interface{}) (float64, bool) {
+	switch x := val.(type) {
+	case int:
+		return float64(x), true
+	case float64:
+		return x, true
+	case bool:
+		if x {
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -0,0 +1,502 @@
+package handlers
+
+import (
+	"comparebuddy-backend/auth"
+	"comparebuddy-backend/config"
+	"comparebuddy-backend/middleware"
+	"comparebuddy-backend/models"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SaveComparisonRequest struct {
+	Title      *string `json:"title"`
+	VariantIDs []int   `json:"variant_ids"`
+}
+
+type ReorderComparisonsRequest struct {
+	IDs []int `json:"ids"`
+}
+
+type ShareComparisonRequest struct {
+	// ExpiresInDays - 0 or omitted means the link never expires
+	ExpiresInDays int `json:"expires_in_days"`
+}
+
+const comparisonColumns = "id, COALESCE(user_id, 0), variant_ids, title, sort_order, share_slug, share_expires_at, view_count, created_at, updated_at"
+
+func scanComparison(scanner interface{ Scan(...interface{}) error }) (models.UserComparison, error) {
+	var cmp models.UserComparison
+	var variantIDs []byte
+	err := scanner.Scan(&cmp.ID, &cmp.UserID, &variantIDs, &cmp.Title, &cmp.SortOrder, &cmp.ShareSlug, &cmp.ShareExpiresAt, &cmp.ViewCount, &cmp.CreatedAt, &cmp.UpdatedAt)
+	if err != nil {
+		return cmp, err
+	}
+	err = json.Unmarshal(variantIDs, &cmp.VariantIDs)
+	return cmp, err
+}
+
+// GetComparisons - GET /api/me/comparisons
+func GetComparisons(c *fiber.Ctx) error {
+	user := middleware.CurrentUser(c)
+
+	rows, err := config.DB.Query(
+		"SELECT "+comparisonColumns+" FROM user_comparisons WHERE user_id = ? ORDER BY sort_order, id",
+		user.ID,
+	)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch comparisons"})
+	}
+	defer rows.Close()
+
+	comparisons := []models.UserComparison{}
+	for rows.Next() {
+		cmp, err := scanComparison(rows)
+		if err != nil {
+			continue
+		}
+		comparisons = append(comparisons, cmp)
+	}
+
+	return c.JSON(comparisons)
+}
+
+// CreateComparison - POST /api/me/comparisons
+func CreateComparison(c *fiber.Ctx) error {
+	user := middleware.CurrentUser(c)
+
+	var req SaveComparisonRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	title, errMsg := normalizeComparisonTitle(req.Title)
+	if errMsg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": errMsg})
+	}
+	if status, errMsg := validateComparisonVariants(req.VariantIDs); errMsg != "" {
+		return c.Status(status).JSON(fiber.Map{"error": errMsg})
+	}
+
+	variantIDs, _ := json.Marshal(req.VariantIDs)
+
+	// New comparisons go to the end of the user's list
+	result, err := config.DB.Exec(
+		`INSERT INTO user_comparisons (user_id, variant_ids, title, sort_order)
+		SELECT ?, ?, ?, COALESCE(MAX(sort_order), -1) + 1 FROM user_comparisons WHERE user_id = ?`,
+		user.ID, string(variantIDs), title, user.ID,
+	)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to save comparison"})
+	}
+
+	id, _ := result.LastInsertId()
+	cmp, err := findComparison(user.ID, int(id))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to load comparison"})
+	}
+
+	return c.Status(201).JSON(cmp)
+}
+
+// UpdateComparison - PUT /api/me/comparisons/:id
+// Renames a comparison and/or replaces its variants.
+func UpdateComparison(c *fiber.Ctx) error {
+	user := middleware.CurrentUser(c)
+
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid comparison id"})
+	}
+
+	var req SaveComparisonRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	sets := []string{}
+	args := []interface{}{}
+
+	if req.Title != nil {
+		title, errMsg := normalizeComparisonTitle(req.Title)
+		if errMsg != "" {
+			return c.Status(400).JSON(fiber.Map{"error": errMsg})
+		}
+		sets = append(sets, "title = ?")
+		args = append(args, title)
+	}
+	if req.VariantIDs != nil {
+		if status, errMsg := validateComparisonVariants(req.VariantIDs); errMsg != "" {
+			return c.Status(status).JSON(fiber.Map{"error": errMsg})
+		}
+		variantIDs, _ := json.Marshal(req.VariantIDs)
+		sets = append(sets, "variant_ids = ?")
+		args = append(args, string(variantIDs))
+	}
+
+	if len(sets) == 0 {
+		return c.Status(400).JSON(fiber.Map{"error": "Nothing to update"})
+	}
+
+	args = append(args, id, user.ID)
+	if _, err := config.DB.Exec("UPDATE user_comparisons SET "+strings.Join(sets, ", ")+" WHERE id = ? AND user_id = ?", args...); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update comparison"})
+	}
+
+	cmp, err := findComparison(user.ID, id)
+	if err == sql.ErrNoRows {
+		return c.Status(404).JSON(fiber.Map{"error": "Comparison not found"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to load comparison"})
+	}
+
+	return c.JSON(cmp)
+}
+
+// ReorderComparisons - PUT /api/me/comparisons/order
+// ids lists the user's comparisons in their new order; comparisons left out keep
+// their relative order after the listed ones.
+func ReorderComparisons(c *fiber.Ctx) error {
+	user := middleware.CurrentUser(c)
+
+	var req ReorderComparisonsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if len(req.IDs) == 0 {
+		return c.Status(400).JSON(fiber.Map{"error": "ids is required"})
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to reorder comparisons"})
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query("SELECT id FROM user_comparisons WHERE user_id = ? ORDER BY sort_order, id FOR UPDATE", user.ID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to reorder comparisons"})
+	}
+	var current []int
+	owned := map[int]bool{}
+	for rows.Next() {
+		var id int
+		rows.Scan(&id)
+		current = append(current, id)
+		owned[id] = true
+	}
+	rows.Close()
+
+	seen := map[int]bool{}
+	order := make([]int, 0, len(current))
+	for _, id := range req.IDs {
+		if !owned[id] {
+			return c.Status(404).JSON(fiber.Map{"error": "Comparison not found", "id": id})
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		order = append(order, id)
+	}
+	for _, id := range current {
+		if !seen[id] {
+			order = append(order, id)
+		}
+	}
+
+	for i, id := range order {
+		if _, err := tx.Exec("UPDATE user_comparisons SET sort_order = ? WHERE id = ? AND user_id = ?", i, id, user.ID); err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to reorder comparisons"})
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to reorder comparisons"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Comparisons reordered", "ids": order})
+}
+
+// DeleteComparison - DELETE /api/me/comparisons/:id
+func DeleteComparison(c *fiber.Ctx) error {
+	user := middleware.CurrentUser(c)
+
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid comparison id"})
+	}
+
+	result, err := config.DB.Exec("DELETE FROM user_comparisons WHERE id = ? AND user_id = ?", id, user.ID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete comparison"})
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return c.Status(404).JSON(fiber.Map{"error": "Comparison not found"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Comparison deleted", "id": id})
+}
+
+// RunComparison - GET /api/me/comparisons/:id/run
+// Returns the same payload as /api/cars/compare for the saved variants, plus which
+// of them have since been discontinued or removed from the catalog.
+func RunComparison(c *fiber.Ctx) error {
+	user := middleware.CurrentUser(c)
+
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid comparison id"})
+	}
+
+	cols, err := parseFieldSelection(c.Query("fields"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	cmp, err := findComparison(user.ID, id)
+	if err == sql.ErrNoRows {
+		return c.Status(404).JSON(fiber.Map{"error": "Comparison not found"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to load comparison"})
+	}
+
+	payload, err := savedComparisonPayload(c, cmp, cols)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch variants for comparison"})
+	}
+	payload["comparison"] = cmp
+
+	return c.JSON(payload)
+}
+
+// ShareComparison - POST /api/me/comparisons/:id/share
+// Publishes the comparison under an unguessable slug. Sharing again keeps the same
+// slug so links already sent stay valid; only the expiry is updated.
+func ShareComparison(c *fiber.Ctx) error {
+	user := middleware.CurrentUser(c)
+
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid comparison id"})
+	}
+
+	var req ShareComparisonRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+	}
+	if req.ExpiresInDays < 0 || req.ExpiresInDays > 3650 {
+		return c.Status(400).JSON(fiber.Map{"error": "expires_in_days must be between 0 and 3650"})
+	}
+
+	cmp, err := findComparison(user.ID, id)
+	if err == sql.ErrNoRows {
+		return c.Status(404).JSON(fiber.Map{"error": "Comparison not found"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to load comparison"})
+	}
+
+	slug := ""
+	if cmp.ShareSlug != nil {
+		slug = *cmp.ShareSlug
+	} else if slug, err = auth.RandomString(12); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to share comparison"})
+	}
+
+	query := "UPDATE user_comparisons SET share_slug = ?, share_expires_at = NULL WHERE id = ? AND user_id = ?"
+	args := []interface{}{slug, id, user.ID}
+	if req.ExpiresInDays > 0 {
+		query = "UPDATE user_comparisons SET share_slug = ?, share_expires_at = DATE_ADD(NOW(), INTERVAL ? DAY) WHERE id = ? AND user_id = ?"
+		args = []interface{}{slug, req.ExpiresInDays, id, user.ID}
+	}
+	if _, err := config.DB.Exec(query, args...); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to share comparison"})
+	}
+
+	if cmp, err = findComparison(user.ID, id); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to load comparison"})
+	}
+
+	return c.JSON(fiber.Map{
+		"slug":       slug,
+		"path":       "/api/compare/s/" + slug,
+		"expires_at": cmp.ShareExpiresAt,
+		"view_count": cmp.ViewCount,
+	})
+}
+
+// UnshareComparison - DELETE /api/me/comparisons/:id/share
+func UnshareComparison(c *fiber.Ctx) error {
+	user := middleware.CurrentUser(c)
+
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid comparison id"})
+	}
+
+	result, err := config.DB.Exec(
+		"UPDATE user_comparisons SET share_slug = NULL, share_expires_at = NULL WHERE id = ? AND user_id = ?",
+		id, user.ID,
+	)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to unshare comparison"})
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		if _, err := findComparison(user.ID, id); err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Comparison not found"})
+		}
+	}
+
+	return c.JSON(fiber.Map{"message": "Comparison is no longer shared", "id": id})
+}
+
+// GetSharedComparison - GET /api/compare/s/:slug (public)
+func GetSharedComparison(c *fiber.Ctx) error {
+	slug := c.Params("slug")
+
+	cols, err := parseFieldSelection(c.Query("fields"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var expired bool
+	var cmp models.UserComparison
+	var variantIDs []byte
+	err = config.DB.QueryRow(
+		`SELECT id, variant_ids, title, share_expires_at, view_count, created_at,
+			share_expires_at IS NOT NULL AND share_expires_at <= NOW()
+		FROM user_comparisons WHERE share_slug = ?`,
+		slug,
+	).Scan(&cmp.ID, &variantIDs, &cmp.Title, &cmp.ShareExpiresAt, &cmp.ViewCount, &cmp.CreatedAt, &expired)
+	if err == sql.ErrNoRows {
+		return c.Status(404).JSON(fiber.Map{"error": "Shared comparison not found"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to load comparison"})
+	}
+	if expired {
+		return c.Status(410).JSON(fiber.Map{"error": "This shared link has expired"})
+	}
+	if err := json.Unmarshal(variantIDs, &cmp.VariantIDs); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to load comparison"})
+	}
+
+	// updated_at = updated_at: a view is not an edit (ON UPDATE CURRENT_TIMESTAMP)
+	if _, err := config.DB.Exec("UPDATE user_comparisons SET view_count = view_count + 1, updated_at = updated_at WHERE id = ?", cmp.ID); err != nil {
+		log.Println("⚠️  Failed to count comparison view:", err)
+	} else {
+		cmp.ViewCount++
+	}
+
+	payload, err := savedComparisonPayload(c, cmp, cols)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch variants for comparison"})
+	}
+	// Only what the public page needs; owner and ordering stay private
+	payload["comparison"] = fiber.Map{
+		"title":       cmp.Title,
+		"variant_ids": cmp.VariantIDs,
+		"created_at":  cmp.CreatedAt,
+		"expires_at":  cmp.ShareExpiresAt,
+		"view_count":  cmp.ViewCount,
+	}
+
+	return c.JSON(payload)
+}
+
+// savedComparisonPayload - re-runs a saved comparison against the current catalog
+// cols is the ?fields= selection; status is always loaded to flag discontinued variants.
+func savedComparisonPayload(c *fiber.Ctx, cmp models.UserComparison, cols []variantColumn) (fiber.Map, error) {
+	variants, err := fetchVariantsByIDs(cmp.VariantIDs, withColumns(compareColumns(c, cols), "status"))
+	if err != nil {
+		return nil, err
+	}
+	if variants == nil {
+		variants = []models.CarVariant{}
+	}
+	markFavoriteVariants(c, variants)
+	markPriceChanges(variants)
+
+	found := map[int]bool{}
+	discontinued := []int{}
+	for _, v := range variants {
+		found[v.ID] = true
+		if v.Status == "discontinued" {
+			discontinued = append(discontinued, v.ID)
+		}
+	}
+	removed := []int{}
+	for _, id := range cmp.VariantIDs {
+		if !found[id] {
+			removed = append(removed, id)
+		}
+	}
+
+	payload := comparePayload(c, variants, cols)
+	payload["discontinued_ids"] = discontinued
+	payload["removed_ids"] = removed
+	return payload, nil
+}
+
+func findComparison(userID, id int) (models.UserComparison, error) {
+	return scanComparison(config.DB.QueryRow(
+		"SELECT "+comparisonColumns+" FROM user_comparisons WHERE id = ? AND user_id = ?",
+		id, userID,
+	))
+}
+
+// normalizeComparisonTitle returns the trimmed title (nil when empty) or a validation message
+func normalizeComparisonTitle(title *string) (*string, string) {
+	if title == nil {
+		return nil, ""
+	}
+	t := strings.TrimSpace(*title)
+	if t == "" {
+		return nil, ""
+	}
+	if utf8.RuneCountInString(t) > 200 {
+		return nil, "Title is too long"
+	}
+	return &t, ""
+}
+
+// validateComparisonVariants returns an HTTP status and message when the IDs are not a valid comparison
+func validateComparisonVariants(ids []int) (int, string) {
+	if len(ids) < minCompareVariants || len(ids) > maxCompareVariants {
+		return 400, "Compare 2-4 variants"
+	}
+
+	seen := map[int]bool{}
+	args := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		if id <= 0 || seen[id] {
+			return 400, "variant_ids must be distinct variant IDs"
+		}
+		seen[id] = true
+		args = append(args, id)
+	}
+
+	placeholders := strings.Repeat("?,", len(ids))
+	placeholders = placeholders[:len(placeholders)-1]
+
+	var count int
+	err := config.DB.QueryRow("SELECT COUNT(*) FROM car_variants WHERE id IN ("+placeholders+")", args...).Scan(&count)
+	if err != nil {
+		return 500, "Failed to validate variants"
+	}
+	if count != len(ids) {
+		return 404, "One or more variants not found"
+	}
+	return 0, ""
+}
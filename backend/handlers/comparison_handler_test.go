@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeComparisonTitle(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name  string
+		title *string
+		want  *string
+		err   bool
+	}{
+		{"missing", nil, nil, false},
+		{"blank", str("   "), nil, false},
+		{"trimmed", str("  EV shortlist "), str("EV shortlist"), false},
+		{"200 Thai characters", str(strings.Repeat("รถ", 100)), str(strings.Repeat("รถ", 100)), false},
+		{"201 characters", str(strings.Repeat("a", 201)), nil, true},
+	}
+	for _, tt := range tests {
+		got, msg := normalizeComparisonTitle(tt.title)
+		if (msg != "") != tt.err || (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+			t.Errorf("%s: normalizeComparisonTitle = %v, %q", tt.name, got, msg)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"comparebuddy-backend/config"
+	"comparebuddy-backend/models"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// facetCount - one option of a facet; Count is 0 for options with no matching
+// variants so the filter UI can disable them
+type facetCount struct {
+	Value interface{} `json:"value"`
+	Label string      `json:"label,omitempty"`
+	Count int         `json:"count"`
+}
+
+type facetRange struct {
+	Min *float64 `json:"min"`
+	Max *float64 `json:"max"`
+}
+
+// Each enum facet is computed over the browse filter minus its own parameter,
+// so picking "suv" still shows how many sedans the rest of the filter would match.
+var enumFacets = []struct {
+	Key     string
+	Expr    string
+	Options []string
+}{
+	{"body_type", "m.body_type", models.BodyTypes},
+	{"segment", "m.segment", models.Segments},
+	{"powertrain_type", "m.powertrain_type", models.PowertrainTypes},
+	{"drive_type", "v.drive_type", models.DriveTypes},
+}
+
+// Range facets are the min/max over the fully filtered result set
+var rangeFacets = []struct {
+	Key  string
+	Expr string
+}{
+	{"price", "v.price_baht"},
+	{"range", "v.range_km"},
+	{"horsepower", "v.horsepower"},
+}
+
+// browseFacets - facet counts and min/max ranges for GET /api/cars/browse.
+// from is the FROM ... WHERE part of the browse query without the filter.
+func browseFacets(params map[string]string, from string, reserved []string) (fiber.Map, error) {
+	facets := fiber.Map{}
+
+	brands, err := brandFacet(params, from, reserved)
+	if err != nil {
+		return nil, err
+	}
+	facets["brand"] = brands
+
+	for _, fc := range enumFacets {
+		filter, err := parseVariantFilters(withoutParams(params, fc.Key), reserved...)
+		if err != nil {
+			return nil, err
+		}
+		counts, err := groupCounts(fc.Expr, from+filter.SQL(), filter.Args)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]facetCount, len(fc.Options))
+		for i, opt := range fc.Options {
+			out[i] = facetCount{Value: opt, Count: counts[opt]}
+		}
+		facets[fc.Key] = out
+	}
+
+	filter, err := parseVariantFilters(params, reserved...)
+	if err != nil {
+		return nil, err
+	}
+	selects := ""
+	ranges := make([]facetRange, len(rangeFacets))
+	dest := make([]interface{}, 0, len(rangeFacets)*2)
+	for i, fc := range rangeFacets {
+		if i > 0 {
+			selects += ", "
+		}
+		selects += "MIN(" + fc.Expr + "), MAX(" + fc.Expr + ")"
+		dest = append(dest, &ranges[i].Min, &ranges[i].Max)
+	}
+	if err := config.DB.QueryRow("SELECT "+selects+from+filter.SQL(), filter.Args...).Scan(dest...); err != nil {
+		return nil, err
+	}
+	for i, fc := range rangeFacets {
+		facets[fc.Key] = ranges[i]
+	}
+
+	return facets, nil
+}
+
+// brandFacet - every brand with its count, including brands with no matches
+func brandFacet(params map[string]string, from string, reserved []string) ([]facetCount, error) {
+	filter, err := parseVariantFilters(withoutParams(params, "brand"), reserved...)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := groupCounts("b.id", from+filter.SQL(), filter.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := config.DB.Query("SELECT id, name FROM car_brands ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []facetCount{}
+	for rows.Next() {
+		var id int
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, err
+		}
+		out = append(out, facetCount{Value: id, Label: name, Count: counts[strconv.Itoa(id)]})
+	}
+	return out, rows.Err()
+}
+
+// groupCounts - COUNT(*) per value of expr, keyed by the value as text
+func groupCounts(expr, fromWhere string, args []interface{}) (map[string]int, error) {
+	rows, err := config.DB.Query("SELECT "+expr+", COUNT(*)"+fromWhere+" GROUP BY "+expr, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := map[string]int{}
+	for rows.Next() {
+		var value *string
+		var n int
+		if err := rows.Scan(&value, &n); err != nil {
+			return nil, err
+		}
+		if value != nil {
+			counts[*value] = n
+		}
+	}
+	return counts, rows.Err()
+}
+
+func withoutParams(params map[string]string, keys ...string) map[string]string {
+	drop := map[string]bool{}
+	for _, k := range keys {
+		drop[k] = true
+	}
+	out := make(map[string]string, len(params))
+	for k, v := range params {
+		if !drop[k] {
+			out[k] = v
+		}
+	}
+	return out
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"comparebuddy-backend/config"
+	"comparebuddy-backend/middleware"
+	"comparebuddy-backend/models"
+	"database/sql"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AddFavoriteRequest struct {
+	VariantID int `json:"variant_id"`
+}
+
+// GetFavorites - GET /api/me/favorites
+func GetFavorites(c *fiber.Ctx) error {
+	user := middleware.CurrentUser(c)
+
+	rows, err := config.DB.Query(
+		`SELECT v.id, v.model_id, v.name, v.price_baht, v.status, b.name, m.name
+		FROM user_car_favorites f
+		JOIN car_variants v ON f.variant_id = v.id
+		JOIN car_models m ON v.model_id = m.id
+		JOIN car_brands b ON m.brand_id = b.id
+		WHERE f.user_id = ?
+		ORDER BY f.created_at DESC, f.id DESC`,
+		user.ID,
+	)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch favorites"})
+	}
+	defer rows.Close()
+
+	favorited := true
+	favorites := []models.CarVariantSummary{}
+	for rows.Next() {
+		var v models.CarVariantSummary
+		rows.Scan(&v.ID, &v.ModelID, &v.Name, &v.PriceBaht, &v.Status, &v.BrandName, &v.ModelName)
+		v.Favorited = &favorited
+		favorites = append(favorites, v)
+	}
+
+	return c.JSON(favorites)
+}
+
+// AddFavorite - POST /api/me/favorites
+// Adding a variant that is already a favorite succeeds without creating a duplicate.
+func AddFavorite(c *fiber.Ctx) error {
+	user := middleware.CurrentUser(c)
+
+	var req AddFavoriteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if req.VariantID <= 0 {
+		return c.Status(400).JSON(fiber.Map{"error": "variant_id is required"})
+	}
+
+	var exists int
+	err := config.DB.QueryRow("SELECT 1 FROM car_variants WHERE id = ?", req.VariantID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return c.Status(404).JSON(fiber.Map{"error": "Variant not found"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to add favorite"})
+	}
+
+	result, err := config.DB.Exec(
+		"INSERT IGNORE INTO user_car_favorites (user_id, variant_id) VALUES (?, ?)",
+		user.ID, req.VariantID,
+	)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to add favorite"})
+	}
+
+	if n, _ := result.RowsAffected(); n == 0 {
+		return c.JSON(fiber.Map{"message": "Already in favorites", "variant_id": req.VariantID})
+	}
+	return c.Status(201).JSON(fiber.Map{"message": "Added to favorites", "variant_id": req.VariantID})
+}
+
+// RemoveFavorite - DELETE /api/me/favorites/:variant_id
+func RemoveFavorite(c *fiber.Ctx) error {
+	user := middleware.CurrentUser(c)
+
+	variantID, err := strconv.Atoi(c.Params("variant_id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid variant id"})
+	}
+
+	if _, err := config.DB.Exec(
+		"DELETE FROM user_car_favorites WHERE user_id = ? AND variant_id = ?",
+		user.ID, variantID,
+	); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to remove favorite"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Removed from favorites", "variant_id": variantID})
+}
+
+// favoriteSet - which of the given variant IDs the user has favorited
+func favoriteSet(userID int, variantIDs []int) (map[int]bool, error) {
+	set := map[int]bool{}
+	if len(variantIDs) == 0 {
+		return set, nil
+	}
+
+	placeholders := strings.Repeat("?,", len(variantIDs))
+	placeholders = placeholders[:len(placeholders)-1]
+
+	args := make([]interface{}, 0, len(variantIDs)+1)
+	args = append(args, userID)
+	for _, id := range variantIDs {
+		args = append(args, id)
+	}
+
+	rows, err := config.DB.Query(
+		"SELECT variant_id FROM user_car_favorites WHERE user_id = ? AND variant_id IN ("+placeholders+")",
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		rows.Scan(&id)
+		set[id] = true
+	}
+	return set, rows.Err()
+}
+
+// markFavoriteVariants - sets the favorited flag when the caller is signed in
+func markFavoriteVariants(c *fiber.Ctx, variants []models.CarVariant) {
+	ids := make([]int, len(variants))
+	for i, v := range variants {
+		ids[i] = v.ID
+	}
+
+	set := favoritedFlags(c, ids)
+	if set == nil {
+		return
+	}
+	for i := range variants {
+		favorited := set[variants[i].ID]
+		variants[i].Favorited = &favorited
+	}
+}
+
+// markFavoriteSummaries - same as markFavoriteVariants for summary rows
+func markFavoriteSummaries(c *fiber.Ctx, variants []models.CarVariantSummary) {
+	ids := make([]int, len(variants))
+	for i, v := range variants {
+		ids[i] = v.ID
+	}
+
+	set := favoritedFlags(c, ids)
+	if set == nil {
+		return
+	}
+	for i := range variants {
+		favorited := set[variants[i].ID]
+		variants[i].Favorited = &favorited
+	}
+}
+
+// favoritedFlags - nil for anonymous callers or when the lookup fails
+func favoritedFlags(c *fiber.Ctx, variantIDs []int) map[int]bool {
+	user := middleware.CurrentUser(c)
+	if user == nil || len(variantIDs) == 0 {
+		return nil
+	}
+
+	set, err := favoriteSet(user.ID, variantIDs)
+	if err != nil {
+		return nil
+	}
+	return set
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"comparebuddy-backend/models"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// parseFieldSelection - ?fields=price_baht,range_km,safety.* -> the columns to select.
+// "<section>.*" expands to every field of that section and "*" to everything.
+// Returns nil (every column) for an empty parameter; id is always included.
+// Only registry keys are accepted, so the result is safe to put into SQL.
+func parseFieldSelection(param string) ([]variantColumn, error) {
+	param = strings.TrimSpace(param)
+	if param == "" {
+		return nil, nil
+	}
+
+	sections := map[string]bool{}
+	for _, s := range models.SpecSections {
+		sections[s.Key] = true
+	}
+
+	wanted := map[string]bool{"id": true}
+	for _, token := range strings.Split(param, ",") {
+		token = strings.TrimSpace(token)
+		switch {
+		case token == "":
+			continue
+		case token == "*":
+			return nil, nil
+		case strings.HasSuffix(token, ".*"):
+			section := strings.TrimSuffix(token, ".*")
+			if !sections[section] {
+				return nil, fmt.Errorf("unknown field section %q", section)
+			}
+			for _, f := range models.SpecFields {
+				if f.Section == section {
+					wanted[f.Key] = true
+				}
+			}
+		default:
+			if _, ok := models.SpecFieldByKey[token]; !ok {
+				return nil, fmt.Errorf("unknown field %q", token)
+			}
+			wanted[token] = true
+		}
+	}
+
+	return filterColumns(wanted), nil
+}
+
+// withColumns - adds keys to a selection; a nil selection already has everything
+func withColumns(cols []variantColumn, keys ...string) []variantColumn {
+	if cols == nil {
+		return nil
+	}
+	wanted := map[string]bool{}
+	for _, col := range cols {
+		wanted[col.Key] = true
+	}
+	for _, key := range keys {
+		wanted[key] = true
+	}
+	return filterColumns(wanted)
+}
+
+// filterColumns - the columns with the given keys, in variantColumnList order
+func filterColumns(wanted map[string]bool) []variantColumn {
+	cols := []variantColumn{}
+	for _, col := range variantColumnList {
+		if wanted[col.Key] {
+			cols = append(cols, col)
+		}
+	}
+	return cols
+}
+
+// variantSelectList - SQL select list for a selection (nil = full spec)
+func variantSelectList(cols []variantColumn) string {
+	if cols == nil {
+		return variantColumns
+	}
+	exprs := make([]string, len(cols))
+	for i, col := range cols {
+		exprs[i] = col.Expr
+	}
+	return strings.Join(exprs, ", ")
+}
+
+// selectionHas - whether a selection (nil = full spec) includes the key
+func selectionHas(cols []variantColumn, key string) bool {
+	if cols == nil {
+		return true
+	}
+	for _, col := range cols {
+		if col.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// projectVariants - serializes only the selected fields; nil selection keeps the full struct
+func projectVariants(variants []models.CarVariant, cols []variantColumn) interface{} {
+	if cols == nil {
+		return variants
+	}
+
+	out := make([]fiber.Map, len(variants))
+	for i := range variants {
+		out[i] = projectVariant(&variants[i], cols)
+	}
+	return out
+}
+
+func projectVariant(v *models.CarVariant, cols []variantColumn) fiber.Map {
+	rv := reflect.ValueOf(v).Elem()
+	m := fiber.Map{}
+	for _, col := range cols {
+		m[col.Key] = rv.Field(col.FieldIndex).Interface()
+	}
+	if v.Favorited != nil {
+		m["favorited"] = v.Favorited
+	}
+	if v.PriceChange != nil {
+		m["price_change"] = v.PriceChange
+	}
+	return m
+}
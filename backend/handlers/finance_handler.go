@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"comparebuddy-backend/config"
+	"database/sql"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Hire-purchase defaults, roughly what Thai lenders advertise
+const (
+	defaultDownPaymentPct = 25.0
+	defaultTermMonths     = 60
+	defaultFlatRate       = 2.49
+
+	maxDownPaymentPct = 90.0
+	minTermMonths     = 6
+	maxTermMonths     = 96
+	maxFlatRate       = 30.0
+)
+
+// installmentRow - one month of a hire-purchase schedule
+type installmentRow struct {
+	Month     int     `json:"month"`
+	Payment   float64 `json:"payment"`
+	Principal float64 `json:"principal"`
+	Interest  float64 `json:"interest"`
+	Balance   float64 `json:"balance"`
+}
+
+// GetVariantInstallment - GET /api/cars/variants/:id/installment?down_payment_pct=25&term_months=60&interest_rate=2.49&color_id=
+// Hire-purchase installment with a flat interest rate (Thai convention):
+// interest = financed amount x rate x years, spread evenly over the term.
+// color_id adds that colour's extra_cost to the financed amount; the down
+// payment is a share of the variant price only.
+func GetVariantInstallment(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid variant ID"})
+	}
+
+	downPct, err := queryFloat(c, "down_payment_pct", defaultDownPaymentPct, 0, maxDownPaymentPct)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	rate, err := queryFloat(c, "interest_rate", defaultFlatRate, 0, maxFlatRate)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	term := defaultTermMonths
+	if s := c.Query("term_months"); s != "" {
+		term, err = strconv.Atoi(s)
+		if err != nil || term < minTermMonths || term > maxTermMonths {
+			return c.Status(400).JSON(fiber.Map{"error": fmt.Sprintf("term_months must be a whole number between %d and %d", minTermMonths, maxTermMonths)})
+		}
+	}
+
+	var price *float64
+	err = config.DB.QueryRow("SELECT price_baht FROM car_variants WHERE id = ?", id).Scan(&price)
+	if err == sql.ErrNoRows {
+		return c.Status(404).JSON(fiber.Map{"error": "Variant not found"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch variant"})
+	}
+	if price == nil {
+		return c.Status(422).JSON(fiber.Map{"error": "This variant has no price yet"})
+	}
+
+	var color fiber.Map
+	extraCost := 0.0
+	if s := c.Query("color_id"); s != "" {
+		colorID, err := strconv.Atoi(s)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid color_id"})
+		}
+		var name string
+		err = config.DB.QueryRow("SELECT name, COALESCE(extra_cost, 0) FROM car_colors WHERE id = ? AND variant_id = ?", colorID, id).Scan(&name, &extraCost)
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{"error": "Color not found for this variant"})
+		}
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch color"})
+		}
+		color = fiber.Map{"id": colorID, "name": name, "extra_cost": extraCost}
+	}
+
+	totalPrice := *price + extraCost
+	downPayment, financed, totalInterest := hirePurchase(*price, extraCost, downPct, rate, term)
+	schedule := flatRateSchedule(financed, totalInterest, term)
+
+	return c.JSON(fiber.Map{
+		"variant_id":       id,
+		"price_baht":       *price,
+		"color":            color,
+		"total_price":      totalPrice,
+		"down_payment_pct": downPct,
+		"down_payment":     downPayment,
+		"financed_amount":  financed,
+		"term_months":      term,
+		"interest_rate":    rate,
+		"effective_rate":   math.Round(effectiveAnnualRate(financed, schedule[0].Payment, term)*100) / 100,
+		"monthly_payment":  schedule[0].Payment,
+		"total_interest":   totalInterest,
+		"total_payable":    downPayment + financed + totalInterest,
+		"schedule":         schedule,
+	})
+}
+
+// hirePurchase - down payment on the price, financed amount including the
+// colour's extra cost, and flat-rate interest on it over the term
+func hirePurchase(price, extraCost, downPct, rate float64, term int) (downPayment, financed, totalInterest float64) {
+	downPayment = roundBaht(price * downPct / 100)
+	financed = price - downPayment + extraCost
+	totalInterest = roundBaht(financed * rate / 100 * float64(term) / 12)
+	return downPayment, financed, totalInterest
+}
+
+// flatRateSchedule - equal monthly payments rounded up to the baht; the last
+// payment absorbs the rounding
+func flatRateSchedule(financed, totalInterest float64, term int) []installmentRow {
+	payment := math.Ceil((financed + totalInterest) / float64(term))
+	principal := roundBaht(financed / float64(term))
+	interest := roundBaht(totalInterest / float64(term))
+
+	rows := make([]installmentRow, term)
+	balance := financed + totalInterest
+	paidPrincipal, paidInterest := 0.0, 0.0
+	for i := range rows {
+		row := installmentRow{Month: i + 1, Payment: payment, Principal: principal, Interest: interest}
+		if i == term-1 {
+			row.Principal = roundBaht(financed - paidPrincipal)
+			row.Interest = roundBaht(totalInterest - paidInterest)
+			row.Payment = roundBaht(row.Principal + row.Interest)
+		} else {
+			// Rounding the payment up goes towards principal
+			row.Principal = roundBaht(payment - interest)
+		}
+		paidPrincipal += row.Principal
+		paidInterest += row.Interest
+		balance = roundBaht(balance - row.Payment)
+		if balance < 0 {
+			balance = 0
+		}
+		row.Balance = balance
+		rows[i] = row
+	}
+	return rows
+}
+
+// effectiveAnnualRate - the reducing-balance (APR) equivalent of a flat-rate
+// loan, in percent, found by bisection on the monthly rate
+func effectiveAnnualRate(financed, payment float64, term int) float64 {
+	if financed <= 0 || payment*float64(term) <= financed {
+		return 0
+	}
+	lo, hi := 0.0, 1.0
+	for i := 0; i < 100; i++ {
+		r := (lo + hi) / 2
+		pv := payment * (1 - math.Pow(1+r, -float64(term))) / r
+		if pv > financed {
+			lo = r
+		} else {
+			hi = r
+		}
+	}
+	return (lo + hi) / 2 * 12 * 100
+}
+
+// queryFloat - optional float query parameter within [min, max]
+func queryFloat(c *fiber.Ctx, param string, def, min, max float64) (float64, error) {
+	s := c.Query(param)
+	if s == "" {
+		return def, nil
+	}
+	x, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(x) || math.IsInf(x, 0) || x < min || x > max {
+		return 0, fmt.Errorf("%s must be a number between %g and %g", param, min, max)
+	}
+	return x, nil
+}
+
+func roundBaht(x float64) float64 {
+	return math.Round(x*100) / 100
+}
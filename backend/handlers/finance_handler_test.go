@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"math"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestFlatRateSchedule(t *testing.T) {
+	got := flatRateSchedule(1000, 100, 3)
+	want := []installmentRow{
+		{Month: 1, Payment: 367, Principal: 333.67, Interest: 33.33, Balance: 733},
+		{Month: 2, Payment: 367, Principal: 333.67, Interest: 33.33, Balance: 366},
+		{Month: 3, Payment: 366, Principal: 332.66, Interest: 33.34, Balance: 0},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("flatRateSchedule(1000, 100, 3) = %+v, want %+v", got, want)
+	}
+}
+
+func TestFlatRateScheduleTotals(t *testing.T) {
+	tests := []struct {
+		financed, interest float64
+		term               int
+	}{
+		{750000, 93375, 60},
+		{1234567.89, 111111.11, 48},
+		{500000, 0, 12},
+		{99999.99, 12345.67, 7},
+	}
+	for _, tt := range tests {
+		rows := flatRateSchedule(tt.financed, tt.interest, tt.term)
+		if len(rows) != tt.term {
+			t.Fatalf("flatRateSchedule(%g, %g, %d): %d rows", tt.financed, tt.interest, tt.term, len(rows))
+		}
+		var payments, principal, interest float64
+		for i, r := range rows {
+			payments += r.Payment
+			principal += r.Principal
+			interest += r.Interest
+			if i < tt.term-1 && r.Payment != rows[0].Payment {
+				t.Errorf("flatRateSchedule(%g, %g, %d): month %d pays %g, month 1 pays %g", tt.financed, tt.interest, tt.term, r.Month, r.Payment, rows[0].Payment)
+			}
+		}
+		if math.Abs(principal-tt.financed) > 0.005 || math.Abs(interest-tt.interest) > 0.005 || math.Abs(payments-tt.financed-tt.interest) > 0.005 {
+			t.Errorf("flatRateSchedule(%g, %g, %d): paid %g principal + %g interest = %g", tt.financed, tt.interest, tt.term, principal, interest, payments)
+		}
+		if last := rows[tt.term-1]; last.Balance != 0 || last.Payment > rows[0].Payment {
+			t.Errorf("flatRateSchedule(%g, %g, %d): last row %+v", tt.financed, tt.interest, tt.term, last)
+		}
+	}
+}
+
+func TestEffectiveAnnualRate(t *testing.T) {
+	tests := []struct {
+		name     string
+		financed float64
+		payment  float64
+		term     int
+		want     float64
+	}{
+		// Annuity payment of 1000 at 12% a year over 12 months
+		{"annuity", 1000, 88.84878867834166, 12, 12},
+		// 10% flat over a year is about 18% on a reducing balance
+		{"flat rate", 1000, 1100.0 / 12, 12, 17.97},
+		{"no interest", 1000, 1000.0 / 12, 12, 0},
+		{"nothing financed", 0, 100, 12, 0},
+	}
+	for _, tt := range tests {
+		if got := effectiveAnnualRate(tt.financed, tt.payment, tt.term); math.Abs(got-tt.want) > 0.01 {
+			t.Errorf("%s: effectiveAnnualRate(%g, %g, %d) = %g, want %g", tt.name, tt.financed, tt.payment, tt.term, got, tt.want)
+		}
+	}
+}
+
+func TestHirePurchase(t *testing.T) {
+	tests := []struct {
+		name                            string
+		price, extraCost, downPct, rate float64
+		term                            int
+		down, financed, interest        float64
+	}{
+		{"no colour", 1000000, 0, 25, 2.49, 60, 250000, 750000, 93375},
+		// The colour's extra cost is financed in full, not split with the down payment
+		{"extra cost colour", 1000000, 20000, 25, 2.49, 60, 250000, 770000, 95865},
+		{"no down payment", 899000, 15000, 0, 3, 48, 0, 914000, 109680},
+	}
+	for _, tt := range tests {
+		down, financed, interest := hirePurchase(tt.price, tt.extraCost, tt.downPct, tt.rate, tt.term)
+		if down != tt.down || financed != tt.financed || interest != tt.interest {
+			t.Errorf("%s: hirePurchase = %g, %g, %g, want %g, %g, %g", tt.name, down, financed, interest, tt.down, tt.financed, tt.interest)
+		}
+	}
+}
+
+func TestQueryFloat(t *testing.T) {
+	tests := []struct {
+		query string
+		want  float64
+		ok    bool
+	}{
+		{"", 25, true},
+		{"down_payment_pct=40", 40, true},
+		{"down_payment_pct=NaN", 0, false},
+		{"down_payment_pct=Inf", 0, false},
+		{"down_payment_pct=95", 0, false},
+		{"down_payment_pct=abc", 0, false},
+	}
+	for _, tt := range tests {
+		var got float64
+		err := withQuery(t, tt.query, func(c *fiber.Ctx) (err error) {
+			got, err = queryFloat(c, "down_payment_pct", defaultDownPaymentPct, 0, maxDownPaymentPct)
+			return err
+		})
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("queryFloat(%q) = %g, %v, want %g ok=%v", tt.query, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestGetVariantInstallmentRejectsBadParams(t *testing.T) {
+	app := fiber.New()
+	app.Get("/variants/:id/installment", GetVariantInstallment)
+
+	for _, query := range []string{"term_months=abc", "term_months=60.5", "term_months=3", "interest_rate=NaN", "down_payment_pct=Inf"} {
+		resp, err := app.Test(httptest.NewRequest("GET", "/variants/1/installment?"+query, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != 400 {
+			t.Errorf("%s: status %d, want 400", query, resp.StatusCode)
+		}
+	}
+}
@@ -7,42 +7,52 @@ import (
 )
 
 func GetItems(c *fiber.Ctx) error {
+	list, err := parseListQuery(c, itemSorts, "id", "brand", 50)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
 	categoryID := c.Query("category_id")
 	brand := c.Query("brand")
 	field := c.Query("field")
 	
-	query := "SELECT id, category_id, brand, name, duration, price, COALESCE(field, '') FROM items WHERE 1=1"
+	from := " FROM items WHERE 1=1"
 	args := []interface{}{}
 	
 	if categoryID != "" {
-		query += " AND category_id = ?"
+		from += " AND category_id = ?"
 		args = append(args, categoryID)
 	}
 	if brand != "" {
-		query += " AND brand LIKE ?"
+		from += " AND brand LIKE ?"
 		args = append(args, "%"+brand+"%")
 	}
 	if field != "" {
-		query += " AND field = ?"
+		from += " AND field = ?"
 		args = append(args, field)
 	}
 	
-	query += " ORDER BY brand, price"
+	total, err := countRows(from, args)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch items"})
+	}
 	
-	rows, err := config.DB.Query(query, args...)
+	page, pageArgs := list.page()
+	query := "SELECT id, category_id, brand, name, COALESCE(duration, ''), price, COALESCE(field, '')" + list.keyColumns() + from + page
+	rows, err := config.DB.Query(query, append(args, pageArgs...)...)
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch items"})
 	}
 	defer rows.Close()
 	
-	var items []models.Item
+	items := []models.Item{}
 	for rows.Next() {
 		var item models.Item
-		rows.Scan(&item.ID, &item.CategoryID, &item.Brand, &item.Name, &item.Duration, &item.Price, &item.Field)
+		rows.Scan(append([]interface{}{&item.ID, &item.CategoryID, &item.Brand, &item.Name, &item.Duration, &item.Price, &item.Field}, list.scanKey()...)...)
 		items = append(items, item)
 	}
 	
-	return c.JSON(items)
+	return c.JSON(listEnvelope(list, items, total))
 }
 
 func GetBrands(c *fiber.Ctx) error {
@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"comparebuddy-backend/config"
+	"comparebuddy-backend/models"
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetVariantImages - GET /api/cars/variants/:id/images?type=exterior,interior
+// The gallery in display order: exterior, interior, detail, color, then sort_order.
+func GetVariantImages(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid variant ID"})
+	}
+
+	var types map[string]bool
+	if param := c.Query("type"); param != "" {
+		valid := map[string]bool{}
+		for _, t := range models.ImageTypes {
+			valid[t] = true
+		}
+		types = map[string]bool{}
+		for _, t := range splitList(param) {
+			t = strings.ToLower(t)
+			if !valid[t] {
+				return c.Status(400).JSON(fiber.Map{"error": "type must be one of " + strings.Join(models.ImageTypes, ", ")})
+			}
+			types[t] = true
+		}
+	}
+
+	if found, err := variantExists(id); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch images"})
+	} else if !found {
+		return c.Status(404).JSON(fiber.Map{"error": "Variant not found"})
+	}
+
+	images, err := variantImages([]int{id})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch images"})
+	}
+
+	out := []models.CarImage{}
+	for _, img := range images[id] {
+		if types == nil || types[img.ImageType] {
+			out = append(out, img)
+		}
+	}
+	return c.JSON(out)
+}
+
+// GetVariantColors - GET /api/cars/variants/:id/colors
+// Colour options, cheapest first, with the price of the car in that colour.
+func GetVariantColors(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid variant ID"})
+	}
+
+	if found, err := variantExists(id); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch colors"})
+	} else if !found {
+		return c.Status(404).JSON(fiber.Map{"error": "Variant not found"})
+	}
+
+	colors, err := variantColors([]int{id})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch colors"})
+	}
+
+	out := colors[id]
+	if out == nil {
+		out = []models.CarColor{}
+	}
+	return c.JSON(out)
+}
+
+// variantImages - galleries of the given variants, keyed by variant ID.
+// ORDER BY image_type follows the ENUM definition, i.e. models.ImageTypes.
+func variantImages(ids []int) (map[int][]models.CarImage, error) {
+	out := map[int][]models.CarImage{}
+	if len(ids) == 0 {
+		return out, nil
+	}
+
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	rows, err := config.DB.Query(
+		`SELECT `+carImageColumns+`
+		FROM car_images
+		WHERE variant_id IN (`+sqlPlaceholders(len(ids))+`)
+		ORDER BY variant_id, image_type, sort_order, id`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		img, err := scanCarImage(rows)
+		if err != nil {
+			return nil, err
+		}
+		out[img.VariantID] = append(out[img.VariantID], img)
+	}
+	return out, rows.Err()
+}
+
+const carImageColumns = "id, variant_id, image_url, COALESCE(image_type, 'exterior'), COALESCE(sort_order, 0), width, height, blurhash, thumbnails"
+
+func scanCarImage(scanner interface{ Scan(...interface{}) error }) (models.CarImage, error) {
+	var img models.CarImage
+	var thumbnails []byte
+	if err := scanner.Scan(&img.ID, &img.VariantID, &img.ImageURL, &img.ImageType, &img.SortOrder,
+		&img.Width, &img.Height, &img.Blurhash, &thumbnails); err != nil {
+		return img, err
+	}
+	if len(thumbnails) > 0 {
+		if err := json.Unmarshal(thumbnails, &img.Thumbnails); err != nil {
+			return img, err
+		}
+	}
+	return img, nil
+}
+
+// variantColors - colour options of the given variants, keyed by variant ID
+func variantColors(ids []int) (map[int][]models.CarColor, error) {
+	out := map[int][]models.CarColor{}
+	if len(ids) == 0 {
+		return out, nil
+	}
+
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	rows, err := config.DB.Query(
+		`SELECT c.id, c.variant_id, c.name, c.hex_code, COALESCE(c.extra_cost, 0),
+			v.price_baht + COALESCE(c.extra_cost, 0)
+		FROM car_colors c
+		JOIN car_variants v ON c.variant_id = v.id
+		WHERE c.variant_id IN (`+sqlPlaceholders(len(ids))+`)
+		ORDER BY c.variant_id, COALESCE(c.extra_cost, 0), c.name, c.id`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var col models.CarColor
+		if err := rows.Scan(&col.ID, &col.VariantID, &col.Name, &col.HexCode, &col.ExtraCost, &col.EffectivePriceBaht); err != nil {
+			return nil, err
+		}
+		out[col.VariantID] = append(out[col.VariantID], col)
+	}
+	return out, rows.Err()
+}
+
+// attachVariantMedia - the variant with its images and colors; both are empty
+// lists, not omitted, when the variant has none
+func attachVariantMedia(v models.CarVariant) (models.CarVariantWithMedia, error) {
+	detail := models.CarVariantWithMedia{CarVariant: v}
+	images, err := variantImages([]int{v.ID})
+	if err != nil {
+		return detail, err
+	}
+	colors, err := variantColors([]int{v.ID})
+	if err != nil {
+		return detail, err
+	}
+	detail.Images, detail.Colors = nonNilImages(images[v.ID]), nonNilColors(colors[v.ID])
+	return detail, nil
+}
+
+// attachSummaryMedia - same as attachVariantMedia for the variants of a model
+func attachSummaryMedia(variants []models.CarVariantSummary) ([]models.CarVariantSummaryWithMedia, error) {
+	ids := make([]int, len(variants))
+	for i, v := range variants {
+		ids[i] = v.ID
+	}
+	images, err := variantImages(ids)
+	if err != nil {
+		return nil, err
+	}
+	colors, err := variantColors(ids)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]models.CarVariantSummaryWithMedia, len(variants))
+	for i, v := range variants {
+		out[i] = models.CarVariantSummaryWithMedia{
+			CarVariantSummary: v,
+			Images:            nonNilImages(images[v.ID]),
+			Colors:            nonNilColors(colors[v.ID]),
+		}
+	}
+	return out, nil
+}
+
+func nonNilImages(images []models.CarImage) []models.CarImage {
+	if images == nil {
+		return []models.CarImage{}
+	}
+	return images
+}
+
+func nonNilColors(colors []models.CarColor) []models.CarColor {
+	if colors == nil {
+		return []models.CarColor{}
+	}
+	return colors
+}
+
+func variantExists(id int) (bool, error) {
+	var exists int
+	err := config.DB.QueryRow("SELECT 1 FROM car_variants WHERE id = ?", id).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -0,0 +1,267 @@
+package handlers
+
+import (
+	"comparebuddy-backend/config"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const maxPageLimit = 100
+
+// variantSorts - ?sort= keys shared by the variant list endpoints (browse, search)
+var variantSorts = map[string][]string{
+	"price":           {"v.price_baht"},
+	"range":           {"v.range_km"},
+	"horsepower":      {"v.horsepower"},
+	"acceleration":    {"v.acceleration_0_100"},
+	"ncap":            {"v.ncap_rating"},
+	"fuel_efficiency": {"v.fuel_consumption_kml"},
+	"battery":         {"v.battery_capacity_kwh"},
+	"name":            {"b.name", "m.name", "v.price_baht"},
+}
+
+// modelSorts - ?sort= keys of GET /api/cars/models
+var modelSorts = map[string][]string{
+	"name": {"b.name", "m.name"},
+	"year": {"m.year_launched"},
+}
+
+// itemSorts - ?sort= keys of GET /api/items
+var itemSorts = map[string][]string{
+	"brand": {"brand", "price"},
+	"price": {"price"},
+	"name":  {"name"},
+}
+
+// listQuery - limit / cursor / sort parameters of a list endpoint
+type listQuery struct {
+	Limit   int
+	SortKey string
+	Desc    bool
+	// After - sort key of the last row of the previous page: the values of the
+	// sort columns, then the tiebreak. Nil on the first page.
+	After    []interface{}
+	columns  []string
+	tiebreak string
+	// keys - sort keys of the rows read for this page, see scanKey
+	keys [][]interface{}
+}
+
+// listCursor - what an opaque cursor carries. Pages continue after the sort key
+// of the last row (keyset pagination), so rows written between two requests
+// do not shift the following pages. The sort is kept so a cursor cannot be
+// replayed against a different ordering.
+type listCursor struct {
+	SortKey string        `json:"s"`
+	Desc    bool          `json:"d,omitempty"`
+	After   []interface{} `json:"a"`
+}
+
+// parseListQuery - reads ?limit=&cursor=&sort= where sort is one of the whitelisted
+// keys, prefixed with "-" for descending order (e.g. sort=-range). defaultSort
+// takes the same form. tiebreak is the unique column ordered on last, e.g. "v.id".
+func parseListQuery(c *fiber.Ctx, sorts map[string][]string, tiebreak, defaultSort string, defaultLimit int) (listQuery, error) {
+	q := listQuery{Limit: defaultLimit, tiebreak: tiebreak}
+
+	if s := c.Query("limit"); s != "" {
+		limit, err := strconv.Atoi(s)
+		if err != nil || limit < 1 || limit > maxPageLimit {
+			return q, fmt.Errorf("limit must be between 1 and %d", maxPageLimit)
+		}
+		q.Limit = limit
+	}
+
+	sortParam := c.Query("sort", defaultSort)
+	q.Desc = strings.HasPrefix(sortParam, "-")
+	q.SortKey = strings.TrimPrefix(sortParam, "-")
+	columns, ok := sorts[q.SortKey]
+	if !ok {
+		keys := make([]string, 0, len(sorts))
+		for k := range sorts {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return q, fmt.Errorf("sort must be one of: %s", strings.Join(keys, ", "))
+	}
+	q.columns = columns
+
+	if s := c.Query("cursor"); s != "" {
+		cur, err := decodeCursor(s)
+		if err != nil || len(cur.After) != len(columns)+1 {
+			return q, fmt.Errorf("invalid cursor")
+		}
+		if cur.SortKey != q.SortKey || cur.Desc != q.Desc {
+			return q, fmt.Errorf("cursor does not match the requested sort")
+		}
+		q.After = cur.After
+	}
+
+	return q, nil
+}
+
+// orderBy - ORDER BY clause with NULLs last and the tiebreak as the final key,
+// so pages are stable
+func (q listQuery) orderBy() string {
+	parts := make([]string, 0, len(q.columns)*2+1)
+	for _, col := range q.columns {
+		dir := ""
+		if q.Desc {
+			dir = " DESC"
+		}
+		parts = append(parts, col+" IS NULL", col+dir)
+	}
+	parts = append(parts, q.tiebreak)
+	return " ORDER BY " + strings.Join(parts, ", ")
+}
+
+// keyset - condition selecting the rows that sort after q.After, in the order
+// of orderBy; "" on the first page. It starts with AND, so the list query must
+// already have a WHERE clause.
+func (q listQuery) keyset() (string, []interface{}) {
+	if q.After == nil {
+		return "", nil
+	}
+	op := " > ?"
+	if q.Desc {
+		op = " < ?"
+	}
+
+	// Row after row: equal on the first i keys and later on key i
+	var either []string
+	var args []interface{}
+	var equal []string
+	var equalArgs []interface{}
+	for i, col := range q.columns {
+		v := q.After[i]
+		if v == nil {
+			// NULLs sort last, so only NULLs can follow a NULL
+			equal = append(equal, col+" IS NULL")
+			continue
+		}
+		either = append(either, conjunction(append(append([]string{}, equal...), "("+col+" IS NULL OR "+col+op+")")))
+		args = append(append(args, equalArgs...), v)
+		equal = append(equal, col+" = ?")
+		equalArgs = append(equalArgs, v)
+	}
+	either = append(either, conjunction(append(equal, q.tiebreak+" > ?")))
+	args = append(append(args, equalArgs...), q.After[len(q.columns)])
+
+	return " AND (" + strings.Join(either, " OR ") + ")", args
+}
+
+// conjunction - conditions joined with AND, parenthesized when there are several
+func conjunction(conditions []string) string {
+	if len(conditions) == 1 {
+		return conditions[0]
+	}
+	return "(" + strings.Join(conditions, " AND ") + ")"
+}
+
+// page - keyset condition, ORDER BY and LIMIT of the list query, and their
+// arguments. One row more than the limit is read to tell if there is a next page.
+func (q listQuery) page() (string, []interface{}) {
+	where, args := q.keyset()
+	return where + q.orderBy() + " LIMIT ?", append(args, q.Limit+1)
+}
+
+// keyColumns - the sort key columns, to append to the SELECT list of the list
+// query and read with scanKey
+func (q listQuery) keyColumns() string {
+	return ", " + strings.Join(append(append([]string{}, q.columns...), q.tiebreak), ", ")
+}
+
+// scanKey - Scan destinations for the keyColumns of one row. The values are
+// kept in q.keys, in row order, for the next cursor.
+func (q *listQuery) scanKey() []interface{} {
+	key := make([]interface{}, len(q.columns)+1)
+	dest := make([]interface{}, len(key))
+	for i := range key {
+		dest[i] = &key[i]
+	}
+	q.keys = append(q.keys, key)
+	return dest
+}
+
+// addKey - records the sort key of a row that was not read with scanKey
+func (q *listQuery) addKey(values ...interface{}) {
+	q.keys = append(q.keys, values)
+}
+
+// listEnvelope - response body shared by every paginated list endpoint. rows
+// are read with page, so there is a next page when it returned more than the limit.
+func listEnvelope[T any](q listQuery, rows []T, total int) fiber.Map {
+	var next interface{}
+	if len(rows) > q.Limit {
+		rows = rows[:q.Limit]
+		next = encodeCursor(listCursor{SortKey: q.SortKey, Desc: q.Desc, After: cursorValues(q.keys[q.Limit-1])})
+	}
+
+	sortParam := q.SortKey
+	if q.Desc {
+		sortParam = "-" + sortParam
+	}
+
+	return fiber.Map{
+		"data":        rows,
+		"next_cursor": next,
+		"total":       total,
+		"limit":       q.Limit,
+		"sort":        sortParam,
+	}
+}
+
+// cursorValues - a sort key as it travels in a cursor: text and DECIMALs as
+// strings, DATETIMEs as MySQL literals (in the connection's time zone, as read)
+func cursorValues(key []interface{}) []interface{} {
+	out := make([]interface{}, len(key))
+	for i, v := range key {
+		switch v := v.(type) {
+		case []byte:
+			out[i] = string(v)
+		case time.Time:
+			out[i] = v.Format("2006-01-02 15:04:05.999999")
+		default:
+			out[i] = v
+		}
+	}
+	return out
+}
+
+// countRows - total matching rows for the envelope; fromWhere is the FROM ... WHERE ...
+// part of the list query
+func countRows(fromWhere string, args []interface{}) (int, error) {
+	var total int
+	err := config.DB.QueryRow("SELECT COUNT(*) "+fromWhere, args...).Scan(&total)
+	return total, err
+}
+
+func encodeCursor(cur listCursor) string {
+	b, _ := json.Marshal(cur)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (listCursor, error) {
+	var cur listCursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return cur, err
+	}
+	if err := json.Unmarshal(b, &cur); err != nil {
+		return cur, err
+	}
+	for _, v := range cur.After {
+		switch v.(type) {
+		case nil, string, float64:
+		default:
+			return cur, fmt.Errorf("invalid cursor value %v", v)
+		}
+	}
+	return cur, nil
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestListQueryKeyset(t *testing.T) {
+	tests := []struct {
+		name  string
+		query listQuery
+		where string
+		args  []interface{}
+	}{
+		{
+			name:  "first page",
+			query: listQuery{columns: []string{"price"}, tiebreak: "id"},
+		},
+		{
+			name:  "ascending",
+			query: listQuery{columns: []string{"price"}, tiebreak: "id", After: []interface{}{"990.00", 7.0}},
+			where: " AND ((price IS NULL OR price > ?) OR (price = ? AND id > ?))",
+			args:  []interface{}{"990.00", "990.00", 7.0},
+		},
+		{
+			name:  "descending keeps the tiebreak ascending",
+			query: listQuery{columns: []string{"v.range_km"}, tiebreak: "v.id", Desc: true, After: []interface{}{400.0, 3.0}},
+			where: " AND ((v.range_km IS NULL OR v.range_km < ?) OR (v.range_km = ? AND v.id > ?))",
+			args:  []interface{}{400.0, 400.0, 3.0},
+		},
+		{
+			name:  "only NULLs follow a NULL",
+			query: listQuery{columns: []string{"v.range_km"}, tiebreak: "v.id", After: []interface{}{nil, 3.0}},
+			where: " AND ((v.range_km IS NULL AND v.id > ?))",
+			args:  []interface{}{3.0},
+		},
+		{
+			name:  "several columns",
+			query: listQuery{columns: []string{"b.name", "m.name"}, tiebreak: "m.id", After: []interface{}{"BYD", "Atto 3", 12.0}},
+			where: " AND ((b.name IS NULL OR b.name > ?) OR (b.name = ? AND (m.name IS NULL OR m.name > ?)) OR (b.name = ? AND m.name = ? AND m.id > ?))",
+			args:  []interface{}{"BYD", "BYD", "Atto 3", "BYD", "Atto 3", 12.0},
+		},
+	}
+	for _, tt := range tests {
+		where, args := tt.query.keyset()
+		if where != tt.where || !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("%s: keyset() = %q %v, want %q %v", tt.name, where, args, tt.where, tt.args)
+		}
+	}
+}
+
+func TestListEnvelopeCursor(t *testing.T) {
+	q := listQuery{Limit: 2, SortKey: "created_at", Desc: true, columns: []string{"a.created_at"}, tiebreak: "a.id"}
+	created := time.Date(2025, 3, 1, 9, 30, 0, 250000000, time.UTC)
+	for i := int64(1); i <= 3; i++ {
+		dest := q.scanKey()
+		*dest[0].(*interface{}) = created
+		*dest[1].(*interface{}) = i
+	}
+
+	result := listEnvelope(q, []int{1, 2, 3}, 10)
+	if data := result["data"].([]int); len(data) != 2 {
+		t.Fatalf("data = %v, want the first 2 rows", data)
+	}
+	next, ok := result["next_cursor"].(string)
+	if !ok {
+		t.Fatalf("next_cursor = %v, want a cursor", result["next_cursor"])
+	}
+	cur, err := decodeCursor(next)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := listCursor{SortKey: "created_at", Desc: true, After: []interface{}{"2025-03-01 09:30:00.25", 2.0}}
+	if !reflect.DeepEqual(cur, want) {
+		t.Errorf("cursor = %+v, want %+v", cur, want)
+	}
+
+	if last := listEnvelope(q, []int{1, 2}, 2); last["next_cursor"] != nil {
+		t.Errorf("next_cursor = %v on the last page, want nil", last["next_cursor"])
+	}
+}
+
+func TestDecodeCursorRejectsNonScalars(t *testing.T) {
+	for _, after := range [][]interface{}{{[]interface{}{1}, 1.0}, {map[string]interface{}{}, 1.0}, {true, 1.0}} {
+		if _, err := decodeCursor(encodeCursor(listCursor{SortKey: "price", After: after})); err == nil {
+			t.Errorf("decodeCursor accepted %v", after)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"comparebuddy-backend/config"
+	"comparebuddy-backend/models"
+	"database/sql"
+	"math"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetVariantPriceHistory - GET /api/cars/variants/:id/price-history
+// Every recorded price of the variant, oldest first.
+func GetVariantPriceHistory(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid variant ID"})
+	}
+
+	var price *float64
+	err = config.DB.QueryRow("SELECT price_baht FROM car_variants WHERE id = ?", id).Scan(&price)
+	if err == sql.ErrNoRows {
+		return c.Status(404).JSON(fiber.Map{"error": "Variant not found"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch price history"})
+	}
+
+	rows, err := config.DB.Query(
+		"SELECT price_baht, previous_price_baht, changed_at FROM car_variant_prices WHERE variant_id = ? ORDER BY changed_at, id",
+		id,
+	)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch price history"})
+	}
+	defer rows.Close()
+
+	history := []models.PricePoint{}
+	for rows.Next() {
+		var p models.PricePoint
+		if err := rows.Scan(&p.PriceBaht, &p.PreviousPriceBaht, &p.ChangedAt); err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch price history"})
+		}
+		history = append(history, p)
+	}
+
+	changes, err := priceChanges([]int{id})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch price history"})
+	}
+
+	return c.JSON(fiber.Map{
+		"variant_id":  id,
+		"price_baht":  price,
+		"last_change": changes[id],
+		"history":     history,
+	})
+}
+
+// priceChanges - the latest actual price change (not the first recorded price)
+// of each variant; variants whose price never changed are left out
+func priceChanges(variantIDs []int) (map[int]*models.PriceChange, error) {
+	changes := map[int]*models.PriceChange{}
+	if len(variantIDs) == 0 {
+		return changes, nil
+	}
+
+	args := make([]interface{}, len(variantIDs))
+	for i, id := range variantIDs {
+		args[i] = id
+	}
+
+	rows, err := config.DB.Query(`SELECT p.variant_id, p.price_baht, p.previous_price_baht, p.changed_at
+		FROM car_variant_prices p
+		JOIN (
+			SELECT variant_id, MAX(id) AS id
+			FROM car_variant_prices
+			WHERE variant_id IN (`+sqlPlaceholders(len(variantIDs))+`) AND previous_price_baht IS NOT NULL
+			GROUP BY variant_id
+		) latest ON latest.id = p.id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		var ch models.PriceChange
+		if err := rows.Scan(&id, &ch.PriceBaht, &ch.PreviousPriceBaht, &ch.ChangedAt); err != nil {
+			return nil, err
+		}
+		if ch.PriceBaht != nil && ch.PreviousPriceBaht != nil {
+			delta := *ch.PriceBaht - *ch.PreviousPriceBaht
+			ch.Delta = &delta
+			if *ch.PreviousPriceBaht != 0 {
+				pct := math.Round(delta / *ch.PreviousPriceBaht * 10000) / 100
+				ch.Percent = &pct
+			}
+		}
+		changes[id] = &ch
+	}
+	return changes, rows.Err()
+}
+
+// markPriceChanges - sets PriceChange on variants. Best effort: a failed lookup
+// leaves the variants unchanged, like markFavoriteVariants.
+func markPriceChanges(variants []models.CarVariant) {
+	ids := make([]int, len(variants))
+	for i, v := range variants {
+		ids[i] = v.ID
+	}
+	changes, err := priceChanges(ids)
+	if err != nil {
+		return
+	}
+	for i := range variants {
+		variants[i].PriceChange = changes[variants[i].ID]
+	}
+}
+
+// markPriceChangeResults - markPriceChanges for browse and search rows
+func markPriceChangeResults(results []variantSearchResult) {
+	ids := make([]int, len(results))
+	for i, r := range results {
+		ids[i] = r.VariantID
+	}
+	changes, err := priceChanges(ids)
+	if err != nil {
+		return
+	}
+	for i := range results {
+		results[i].PriceChange = changes[results[i].VariantID]
+	}
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"comparebuddy-backend/config"
+	"comparebuddy-backend/middleware"
+	"comparebuddy-backend/models"
+	"comparebuddy-backend/rbac"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type GrantRoleRequest struct {
+	Role string `json:"role"`
+}
+
+// GetRoles - GET /api/admin/roles
+func GetRoles(c *fiber.Ctx) error {
+	rows, err := config.DB.Query(
+		`SELECT r.id, r.name, r.description, p.name
+		FROM roles r
+		LEFT JOIN role_permissions rp ON rp.role_id = r.id
+		LEFT JOIN permissions p ON rp.permission_id = p.id
+		ORDER BY r.id, p.name`,
+	)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch roles"})
+	}
+	defer rows.Close()
+
+	roles := []models.Role{}
+	for rows.Next() {
+		var r models.Role
+		var perm *string
+		if err := rows.Scan(&r.ID, &r.Name, &r.Description, &perm); err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch roles"})
+		}
+		if len(roles) == 0 || roles[len(roles)-1].ID != r.ID {
+			r.Permissions = []string{}
+			roles = append(roles, r)
+		}
+		if perm != nil {
+			last := &roles[len(roles)-1]
+			last.Permissions = append(last.Permissions, *perm)
+		}
+	}
+
+	return c.JSON(roles)
+}
+
+// GetUserRoles - GET /api/admin/users/:id/roles
+func GetUserRoles(c *fiber.Ctx) error {
+	userID, status, err := roleTargetUser(c)
+	if err != nil {
+		return c.Status(status).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	roles, permissions, err := rbac.Access(userID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch roles"})
+	}
+	return c.JSON(fiber.Map{"user_id": userID, "roles": roles, "permissions": permissions})
+}
+
+// GrantUserRole - POST /api/admin/users/:id/roles
+// Granting a role the user already has succeeds without a change.
+func GrantUserRole(c *fiber.Ctx) error {
+	admin := middleware.CurrentUser(c)
+
+	userID, status, err := roleTargetUser(c)
+	if err != nil {
+		return c.Status(status).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var req GrantRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	role := strings.ToLower(strings.TrimSpace(req.Role))
+	if role == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "role is required"})
+	}
+
+	granted, err := rbac.Grant(userID, role, &admin.ID)
+	switch {
+	case err == rbac.ErrUnknownRole:
+		return c.Status(404).JSON(fiber.Map{"error": "Role not found"})
+	case err == rbac.ErrImplicitRole:
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	case err != nil:
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to grant role"})
+	}
+
+	if !granted {
+		return c.JSON(fiber.Map{"message": "User already has this role", "user_id": userID, "role": role})
+	}
+	return c.Status(201).JSON(fiber.Map{"message": "Role granted", "user_id": userID, "role": role})
+}
+
+// RevokeUserRole - DELETE /api/admin/users/:id/roles/:role
+func RevokeUserRole(c *fiber.Ctx) error {
+	userID, status, err := roleTargetUser(c)
+	if err != nil {
+		return c.Status(status).JSON(fiber.Map{"error": err.Error()})
+	}
+	role := strings.ToLower(c.Params("role"))
+
+	revoked, err := rbac.Revoke(userID, role)
+	switch {
+	case err == rbac.ErrUnknownRole:
+		return c.Status(404).JSON(fiber.Map{"error": "Role not found"})
+	case err == rbac.ErrImplicitRole:
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	case err == rbac.ErrLastAdmin:
+		return c.Status(409).JSON(fiber.Map{"error": err.Error()})
+	case err != nil:
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to revoke role"})
+	}
+
+	if !revoked {
+		return c.Status(404).JSON(fiber.Map{"error": "User does not have this role"})
+	}
+	return c.JSON(fiber.Map{"message": "Role revoked", "user_id": userID, "role": role})
+}
+
+// roleTargetUser - the :id user of a role endpoint, with the error status when it is invalid
+func roleTargetUser(c *fiber.Ctx) (int, int, error) {
+	userID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return 0, 400, errors.New("Invalid user ID")
+	}
+
+	var exists int
+	err = config.DB.QueryRow("SELECT 1 FROM users WHERE id = ?", userID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return 0, 404, errors.New("User not found")
+	}
+	if err != nil {
+		return 0, 500, errors.New("Failed to fetch user")
+	}
+	return userID, 0, nil
+}
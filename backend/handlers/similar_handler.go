@@ -0,0 +1,304 @@
+package handlers
+
+import (
+	"comparebuddy-backend/config"
+	"comparebuddy-backend/models"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	defaultSimilarLimit = 6
+	maxSimilarLimit     = 20
+	maxSimilarWeight    = 10.0
+
+	// kwToHP - metric horsepower per kilowatt
+	kwToHP = 1.341
+)
+
+// similarityDimension - one term of the distance between two variants, scaled to 0..1
+type similarityDimension struct {
+	Key     string  // weight query parameter: w_<key>
+	Default float64 // default weight
+	// Fields - numeric spec fields (or derived values, see similarityValue) of a
+	// numeric dimension; their normalized differences are averaged. Empty for
+	// categorical dimensions.
+	Fields []string
+}
+
+var similarityDimensions = []similarityDimension{
+	{Key: "price", Default: 3, Fields: []string{"price_baht"}},
+	{Key: "size", Default: 1, Fields: []string{"length_mm", "width_mm", "wheelbase_mm"}},
+	{Key: "power", Default: 1, Fields: []string{"power_hp"}},
+	{Key: "range", Default: 1, Fields: []string{"range_km"}},
+	{Key: "segment", Default: 1},
+	{Key: "body_type", Default: 2},
+}
+
+// similarFields - the spec fields a similarity search loads for every candidate
+var similarFields = map[string]bool{
+	"id": true, "model_id": true, "name": true, "brand_name": true, "model_name": true,
+	"price_baht": true, "status": true, "powertrain_type": true, "body_type": true, "segment": true,
+	"length_mm": true, "width_mm": true, "wheelbase_mm": true, "range_km": true,
+	"horsepower": true, "system_power_hp": true, "motor_power_kw": true,
+}
+
+// GetSimilarVariants - GET /api/cars/variants/:id/similar?limit=6&w_price=3&w_range=0
+// Nearest variants of other models by a weighted distance over normalized specs.
+// Weights (0-10) default to price 3, body_type 2, size/power/range/segment 1.
+// Discontinued variants are left out unless include_discontinued=true.
+func GetSimilarVariants(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid variant ID"})
+	}
+
+	limit := defaultSimilarLimit
+	if s := c.Query("limit"); s != "" {
+		limit, err = strconv.Atoi(s)
+		if err != nil || limit < 1 || limit > maxSimilarLimit {
+			return c.Status(400).JSON(fiber.Map{"error": fmt.Sprintf("limit must be between 1 and %d", maxSimilarLimit)})
+		}
+	}
+
+	weights := map[string]float64{}
+	for _, dim := range similarityDimensions {
+		weights[dim.Key] = dim.Default
+		if s := c.Query("w_" + dim.Key); s != "" {
+			w, err := strconv.ParseFloat(s, 64)
+			if err != nil || math.IsNaN(w) || math.IsInf(w, 0) || w < 0 || w > maxSimilarWeight {
+				return c.Status(400).JSON(fiber.Map{"error": fmt.Sprintf("w_%s must be a number between 0 and %g", dim.Key, maxSimilarWeight)})
+			}
+			weights[dim.Key] = w
+		}
+	}
+
+	variants, spread, err := similarCatalog.get()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch similar variants"})
+	}
+
+	var target *models.CarVariant
+	for i := range variants {
+		if variants[i].ID == id {
+			target = &variants[i]
+		}
+	}
+	if target == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Variant not found"})
+	}
+
+	includeDiscontinued := c.QueryBool("include_discontinued")
+	var candidates []models.CarVariant
+	for _, v := range variants {
+		if v.ModelID == target.ModelID || (!includeDiscontinued && v.Status == "discontinued") {
+			continue
+		}
+		candidates = append(candidates, v)
+	}
+
+	type scored struct {
+		variant  models.CarVariant
+		distance float64
+	}
+	results := make([]scored, 0, len(candidates))
+	for _, v := range candidates {
+		results = append(results, scored{v, variantDistance(target, &v, weights, spread)})
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].distance < results[j].distance
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	markVariants := make([]models.CarVariant, len(results))
+	for i, r := range results {
+		markVariants[i] = r.variant
+	}
+	markFavoriteVariants(c, markVariants)
+
+	similar := make([]fiber.Map, len(results))
+	for i, r := range results {
+		item := projectVariant(&markVariants[i], similarCatalog.columns)
+		item["distance"] = math.Round(r.distance*1000) / 1000
+		item["similarity"] = math.Round((1-r.distance)*1000) / 1000
+		similar[i] = item
+	}
+
+	return c.JSON(fiber.Map{
+		"variant_id": id,
+		"weights":    weights,
+		"similar":    similar,
+	})
+}
+
+// similarCatalog - every variant with the similarFields, and the spread of each
+// numeric field, loaded once and shared by all similarity requests instead of
+// per request. Reloaded after five minutes, like the search indexes, or after
+// a catalog write (catalogTable.written).
+var similarCatalog = &variantCatalog{ttl: 5 * time.Minute}
+
+type variantCatalog struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	columns  []variantColumn
+	variants []models.CarVariant
+	spread   map[string]float64
+	builtAt  time.Time
+}
+
+// Invalidate - forces a reload on the next request
+func (vc *variantCatalog) Invalidate() {
+	vc.mu.Lock()
+	vc.builtAt = time.Time{}
+	vc.mu.Unlock()
+}
+
+// get - the cached variants and spreads, reloaded when stale. Callers must
+// not modify the returned slice or map.
+func (vc *variantCatalog) get() ([]models.CarVariant, map[string]float64, error) {
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
+	if !vc.builtAt.IsZero() && time.Since(vc.builtAt) < vc.ttl {
+		return vc.variants, vc.spread, nil
+	}
+
+	if vc.columns == nil {
+		vc.columns = filterColumns(similarFields)
+	}
+	query := "SELECT " + variantSelectList(vc.columns) + " FROM car_variants v JOIN car_models m ON v.model_id = m.id JOIN car_brands b ON m.brand_id = b.id"
+	rows, err := config.DB.Query(query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var variants []models.CarVariant
+	for rows.Next() {
+		v, err := scanVariantColumns(rows, vc.columns)
+		if err != nil {
+			return nil, nil, err
+		}
+		variants = append(variants, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	// Spread of every numeric field over the catalog, used to scale differences
+	spread := map[string]float64{}
+	for _, dim := range similarityDimensions {
+		for _, key := range dim.Fields {
+			spread[key] = fieldSpread(variants, key)
+		}
+	}
+
+	vc.variants, vc.spread, vc.builtAt = variants, spread, time.Now()
+	return variants, spread, nil
+}
+
+// variantDistance - weighted root mean square of the per-dimension differences, 0..1
+func variantDistance(a, b *models.CarVariant, weights map[string]float64, spread map[string]float64) float64 {
+	var sum, total float64
+	for _, dim := range similarityDimensions {
+		w := weights[dim.Key]
+		if w == 0 {
+			continue
+		}
+		var d float64
+		switch dim.Key {
+		case "segment":
+			d = categoryDistance(a.Segment, b.Segment)
+		case "body_type":
+			d = bodyTypeDistance(a.BodyType, b.BodyType)
+		default:
+			for _, key := range dim.Fields {
+				d += numericDistance(similarityValue(a, key), similarityValue(b, key), spread[key])
+			}
+			d /= float64(len(dim.Fields))
+		}
+		sum += w * d * d
+		total += w
+	}
+	if total == 0 {
+		return 0
+	}
+	return math.Sqrt(sum / total)
+}
+
+// numericDistance - |a-b| scaled by the catalog spread and capped at 1. A value
+// present on only one side counts as the largest difference (e.g. range on a
+// BEV against an ICE car); missing on both sides counts as equal.
+func numericDistance(a, b interface{}, spread float64) float64 {
+	x, okA := comparableValue(a)
+	y, okB := comparableValue(b)
+	switch {
+	case !okA && !okB:
+		return 0
+	case !okA || !okB:
+		return 1
+	case spread == 0:
+		return 0
+	}
+	return math.Min(math.Abs(x-y)/spread, 1)
+}
+
+func categoryDistance(a, b *string) float64 {
+	if a == nil || b == nil || *a != *b {
+		return 1
+	}
+	return 0
+}
+
+// bodyTypeDistance - like categoryDistance, but SUVs and crossovers are close
+func bodyTypeDistance(a, b *string) float64 {
+	if a != nil && b != nil && *a != *b {
+		pair := map[string]bool{*a: true, *b: true}
+		if pair["suv"] && pair["crossover"] {
+			return 0.5
+		}
+	}
+	return categoryDistance(a, b)
+}
+
+// fieldSpread - max - min of a numeric field over variants
+func fieldSpread(variants []models.CarVariant, key string) float64 {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for i := range variants {
+		x, ok := comparableValue(similarityValue(&variants[i], key))
+		if !ok {
+			continue
+		}
+		lo = math.Min(lo, x)
+		hi = math.Max(hi, x)
+	}
+	if hi < lo {
+		return 0
+	}
+	return hi - lo
+}
+
+// similarityValue - a spec field of v, or the derived "power_hp": engine
+// horsepower, else hybrid system power, else electric motor power. BEVs only
+// have motor_power_kw, so comparing horsepower alone would leave them out.
+func similarityValue(v *models.CarVariant, key string) interface{} {
+	if key != "power_hp" {
+		return variantFieldValue(v, key)
+	}
+	switch {
+	case v.Horsepower != nil:
+		return float64(*v.Horsepower)
+	case v.SystemPowerHp != nil:
+		return float64(*v.SystemPowerHp)
+	case v.MotorPowerKw != nil:
+		return *v.MotorPowerKw * kwToHP
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"comparebuddy-backend/models"
+	"math"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestSimilarityPower(t *testing.T) {
+	hp := func(v int) *int { return &v }
+	kw := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name string
+		v    models.CarVariant
+		want interface{}
+	}{
+		{"ICE", models.CarVariant{Horsepower: hp(201)}, 201.0},
+		{"hybrid without engine horsepower", models.CarVariant{SystemPowerHp: hp(218)}, 218.0},
+		{"engine horsepower first", models.CarVariant{Horsepower: hp(98), SystemPowerHp: hp(218), MotorPowerKw: kw(134)}, 98.0},
+		{"BEV", models.CarVariant{MotorPowerKw: kw(150)}, 150 * kwToHP},
+		{"unknown", models.CarVariant{}, nil},
+	}
+	for _, tt := range tests {
+		if got := similarityValue(&tt.v, "power_hp"); got != tt.want {
+			t.Errorf("%s: power_hp = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestVariantDistancePowerAcrossPowertrains(t *testing.T) {
+	hp := func(v int) *int { return &v }
+	kw := func(v float64) *float64 { return &v }
+
+	bev := models.CarVariant{MotorPowerKw: kw(150)}
+	ice := models.CarVariant{Horsepower: hp(201)}
+	weak := models.CarVariant{Horsepower: hp(101)}
+	variants := []models.CarVariant{bev, ice, weak}
+	spread := map[string]float64{"power_hp": fieldSpread(variants, "power_hp")}
+	weights := map[string]float64{"power": 1}
+
+	// 150 kW is about 201 hp: a BEV and an ICE car of equal power are close
+	if d := variantDistance(&bev, &ice, weights, spread); d > 0.01 {
+		t.Errorf("distance(BEV 150 kW, ICE 201 hp) = %g, want about 0", d)
+	}
+	if d := variantDistance(&bev, &weak, weights, spread); math.Abs(d-1) > 0.01 {
+		t.Errorf("distance(BEV 150 kW, ICE 101 hp) = %g, want about 1", d)
+	}
+}
+
+func TestGetSimilarVariantsRejectsBadParams(t *testing.T) {
+	app := fiber.New()
+	app.Get("/variants/:id/similar", GetSimilarVariants)
+
+	for _, query := range []string{"limit=abc", "limit=0", "limit=21", "w_price=NaN", "w_range=Inf", "w_size=-1"} {
+		resp, err := app.Test(httptest.NewRequest("GET", "/variants/1/similar?"+query, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != 400 {
+			t.Errorf("%s: status %d, want 400", query, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"comparebuddy-backend/models"
+	"comparebuddy-backend/tco"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const maxTCOYears = 15
+
+// tcoFields - the spec fields a TCO calculation reads
+var tcoFields = map[string]bool{
+	"id": true, "name": true, "brand_name": true, "model_name": true, "powertrain_type": true,
+	"price_baht": true, "battery_capacity_kwh": true, "range_km": true, "ev_range_km": true,
+	"fuel_consumption_kml": true, "displacement_cc": true, "curb_weight_kg": true,
+}
+
+// GetVariantTCO - GET /api/cars/tco?ids=1,3&km_per_year=20000&years=5&electricity_price=4.5&dc_price=7.5&home_share=0.8&fuel_price=38
+// Total cost of ownership for one variant or a compare set (up to 4), year by
+// year. Inputs that are left out fall back to the defaults in the TCO tables.
+func GetVariantTCO(c *fiber.Ctx) error {
+	idsParam := c.Query("ids")
+	if idsParam == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "ids parameter is required (e.g. ?ids=1,3)"})
+	}
+	ids, err := parseIDList(idsParam)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "ids must be a comma separated list of variant IDs"})
+	}
+	if len(ids) > maxCompareVariants {
+		return c.Status(400).JSON(fiber.Map{"error": fmt.Sprintf("At most %d variants per calculation", maxCompareVariants)})
+	}
+
+	tables := tco.Current()
+	inputs, err := parseTCOInputs(c, tables.Defaults)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	variants, err := fetchVariantsByIDs(ids, filterColumns(tcoFields))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch variants"})
+	}
+	if len(variants) == 0 {
+		return c.Status(404).JSON(fiber.Map{"error": "No variants found"})
+	}
+
+	results := make([]fiber.Map, len(variants))
+	for i, v := range variants {
+		results[i] = fiber.Map{
+			"id":              v.ID,
+			"name":            v.Name,
+			"brand_name":      v.BrandName,
+			"model_name":      v.ModelName,
+			"powertrain_type": v.PowertrainType,
+			"tco":             tco.Calculate(tcoVehicle(&v), inputs, tables),
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"inputs":   inputs,
+		"variants": results,
+	})
+}
+
+func tcoVehicle(v *models.CarVariant) tco.Vehicle {
+	vehicle := tco.Vehicle{
+		PriceBaht:          v.PriceBaht,
+		BatteryCapacityKwh: v.BatteryCapacityKwh,
+		RangeKm:            v.RangeKm,
+		EvRangeKm:          v.EvRangeKm,
+		FuelConsumptionKml: v.FuelConsumptionKml,
+		DisplacementCc:     v.DisplacementCc,
+		CurbWeightKg:       v.CurbWeightKg,
+	}
+	if v.PowertrainType != nil {
+		vehicle.PowertrainType = *v.PowertrainType
+	}
+	return vehicle
+}
+
+// parseTCOInputs - query parameters on top of the defaults, range-checked
+func parseTCOInputs(c *fiber.Ctx, in tco.Inputs) (tco.Inputs, error) {
+	ints := []struct {
+		param    string
+		dest     *int
+		min, max int
+	}{
+		{"km_per_year", &in.KmPerYear, 1, 200000},
+		{"years", &in.Years, 1, maxTCOYears},
+	}
+	for _, p := range ints {
+		if s := c.Query(p.param); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil || n < p.min || n > p.max {
+				return in, fmt.Errorf("%s must be a whole number between %d and %d", p.param, p.min, p.max)
+			}
+			*p.dest = n
+		}
+	}
+
+	floats := []struct {
+		param    string
+		dest     *float64
+		min, max float64
+	}{
+		{"electricity_price", &in.ElectricityPrice, 0, 100},
+		{"dc_price", &in.DCPrice, 0, 100},
+		{"home_share", &in.HomeShare, 0, 1},
+		{"fuel_price", &in.FuelPrice, 0, 200},
+		{"phev_electric_share", &in.PHEVElectricShare, 0, 1},
+	}
+	for _, p := range floats {
+		if s := c.Query(p.param); s != "" {
+			x, err := strconv.ParseFloat(s, 64)
+			if err != nil || math.IsNaN(x) || math.IsInf(x, 0) || x < p.min || x > p.max {
+				return in, fmt.Errorf("%s must be a number between %g and %g", p.param, p.min, p.max)
+			}
+			*p.dest = x
+		}
+	}
+	return in, nil
+}
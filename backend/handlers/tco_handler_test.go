@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"comparebuddy-backend/tco"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// withQuery - runs fn on a request with the given query string and returns
+// the error it produced
+func withQuery(t *testing.T, query string, fn func(c *fiber.Ctx) error) error {
+	t.Helper()
+	var result error
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		result = fn(c)
+		return nil
+	})
+	if _, err := app.Test(httptest.NewRequest("GET", "/?"+query, nil)); err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestParseTCOInputs(t *testing.T) {
+	tests := []struct {
+		query string
+		ok    bool
+	}{
+		{"", true},
+		{"fuel_price=41.5&home_share=1&years=10", true},
+		{"fuel_price=NaN", false},
+		{"electricity_price=Inf", false},
+		{"dc_price=-Inf", false},
+		{"home_share=1.5", false},
+		{"years=2.5", false},
+		{"km_per_year=0", false},
+	}
+	for _, tt := range tests {
+		err := withQuery(t, tt.query, func(c *fiber.Ctx) error {
+			_, err := parseTCOInputs(c, tco.DefaultTables().Defaults)
+			return err
+		})
+		if (err == nil) != tt.ok {
+			t.Errorf("parseTCOInputs(%q) error = %v, want ok=%v", tt.query, err, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"comparebuddy-backend/audit"
+	"comparebuddy-backend/auth"
+	"comparebuddy-backend/config"
+	"comparebuddy-backend/media"
+	"comparebuddy-backend/middleware"
+	"comparebuddy-backend/models"
+	"comparebuddy-backend/storage"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReorderImagesRequest struct {
+	IDs []int `json:"ids"`
+}
+
+// UploadVariantImage - POST /api/cars/variants/:id/images (multipart: file, image_type, sort_order)
+// Stores the image with its thumbnails and blurhash. Without sort_order the
+// image goes after the variant's other images of the same type.
+func UploadVariantImage(c *fiber.Ctx) error {
+	user := middleware.CurrentUser(c)
+
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid variant ID"})
+	}
+
+	imageType := strings.ToLower(c.FormValue("image_type", "exterior"))
+	validType := false
+	for _, t := range models.ImageTypes {
+		validType = validType || t == imageType
+	}
+	if !validType {
+		return c.Status(400).JSON(fiber.Map{"error": "image_type must be one of " + strings.Join(models.ImageTypes, ", ")})
+	}
+
+	if found, err := variantExists(id); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to upload image"})
+	} else if !found {
+		return c.Status(404).JSON(fiber.Map{"error": "Variant not found"})
+	}
+
+	var sortOrder int
+	if s := c.FormValue("sort_order"); s != "" {
+		if sortOrder, err = strconv.Atoi(s); err != nil || sortOrder < 0 {
+			return c.Status(400).JSON(fiber.Map{"error": "sort_order must be a non-negative integer"})
+		}
+	} else if err := config.DB.QueryRow(
+		"SELECT COALESCE(MAX(sort_order) + 1, 0) FROM car_images WHERE variant_id = ? AND image_type = ?",
+		id, imageType,
+	).Scan(&sortOrder); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to upload image"})
+	}
+
+	stored, status, err := storeUpload(c, fmt.Sprintf("variants/%d", id))
+	if err != nil {
+		return c.Status(status).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	thumbnails, _ := json.Marshal(stored.Thumbnails)
+	result, err := config.DB.Exec(
+		`INSERT INTO car_images (variant_id, image_url, storage_key, image_type, sort_order, width, height, blurhash, thumbnails, uploaded_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		id, stored.URL, stored.Key, imageType, sortOrder, stored.Width, stored.Height, stored.Blurhash, string(thumbnails), user.ID,
+	)
+	if err != nil {
+		media.Remove(c.Context(), storage.Current(), stored.Key)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to upload image"})
+	}
+	imageID, _ := result.LastInsertId()
+
+	img, err := scanCarImage(config.DB.QueryRow("SELECT "+carImageColumns+" FROM car_images WHERE id = ?", imageID))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to upload image"})
+	}
+	return c.Status(201).JSON(img)
+}
+
+// ReorderVariantImages - PUT /api/cars/variants/:id/images/order
+// ids lists images in their new order; images left out keep their relative
+// order after the listed ones. Order only matters within an image_type.
+func ReorderVariantImages(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid variant ID"})
+	}
+
+	var req ReorderImagesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if len(req.IDs) == 0 {
+		return c.Status(400).JSON(fiber.Map{"error": "ids is required"})
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to reorder images"})
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query("SELECT id FROM car_images WHERE variant_id = ? ORDER BY image_type, sort_order, id FOR UPDATE", id)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to reorder images"})
+	}
+	var current []int
+	owned := map[int]bool{}
+	for rows.Next() {
+		var imageID int
+		rows.Scan(&imageID)
+		current = append(current, imageID)
+		owned[imageID] = true
+	}
+	rows.Close()
+
+	seen := map[int]bool{}
+	order := make([]int, 0, len(current))
+	for _, imageID := range req.IDs {
+		if !owned[imageID] {
+			return c.Status(404).JSON(fiber.Map{"error": "Image not found", "id": imageID})
+		}
+		if seen[imageID] {
+			continue
+		}
+		seen[imageID] = true
+		order = append(order, imageID)
+	}
+	for _, imageID := range current {
+		if !seen[imageID] {
+			order = append(order, imageID)
+		}
+	}
+
+	for i, imageID := range order {
+		if _, err := tx.Exec("UPDATE car_images SET sort_order = ? WHERE id = ? AND variant_id = ?", i, imageID, id); err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to reorder images"})
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to reorder images"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Images reordered", "ids": order})
+}
+
+// DeleteCarImage - DELETE /api/cars/images/:id
+// Uploaded files are removed from storage too; rows added by hand only lose the row.
+func DeleteCarImage(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid image ID"})
+	}
+
+	var key *string
+	err = config.DB.QueryRow("SELECT storage_key FROM car_images WHERE id = ?", id).Scan(&key)
+	if err == sql.ErrNoRows {
+		return c.Status(404).JSON(fiber.Map{"error": "Image not found"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete image"})
+	}
+
+	if _, err := config.DB.Exec("DELETE FROM car_images WHERE id = ?", id); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete image"})
+	}
+	if key != nil {
+		// The row is gone either way; a file left behind is only wasted space
+		if err := media.Remove(c.Context(), storage.Current(), *key); err != nil {
+			log.Printf("⚠️  Failed to remove stored image %s: %v", *key, err)
+		}
+	}
+
+	return c.JSON(fiber.Map{"message": "Image deleted", "id": id})
+}
+
+// UploadBrandLogo - POST /api/cars/brands/:id/logo (multipart: file)
+// Replaces car_brands.logo_url; a previously uploaded logo is removed from storage.
+func UploadBrandLogo(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid brand ID"})
+	}
+
+	var oldKey *string
+	err = config.DB.QueryRow("SELECT logo_storage_key FROM car_brands WHERE id = ?", id).Scan(&oldKey)
+	if err == sql.ErrNoRows {
+		return c.Status(404).JSON(fiber.Map{"error": "Brand not found"})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to upload logo"})
+	}
+
+	stored, status, err := storeUpload(c, fmt.Sprintf("brands/%d", id))
+	if err != nil {
+		return c.Status(status).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if err := setBrandLogo(c, id, stored); err != nil {
+		media.Remove(c.Context(), storage.Current(), stored.Key)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to upload logo"})
+	}
+	if oldKey != nil {
+		if err := media.Remove(c.Context(), storage.Current(), *oldKey); err != nil {
+			log.Printf("⚠️  Failed to remove old logo %s: %v", *oldKey, err)
+		}
+	}
+
+	return c.Status(201).JSON(fiber.Map{
+		"brand_id":   id,
+		"logo_url":   stored.URL,
+		"width":      stored.Width,
+		"height":     stored.Height,
+		"blurhash":   stored.Blurhash,
+		"thumbnails": stored.Thumbnails,
+	})
+}
+
+// setBrandLogo - points the brand at the stored logo, audited like other brand writes
+func setBrandLogo(c *fiber.Ctx, id int, stored *media.Stored) error {
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := audit.Snapshots(tx, "car_brands", "id = ? FOR UPDATE", id)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(
+		"UPDATE car_brands SET logo_url = ?, logo_storage_key = ? WHERE id = ?",
+		stored.URL, stored.Key, id,
+	); err != nil {
+		return err
+	}
+	after, err := audit.Snapshots(tx, "car_brands", "id = ?", id)
+	if err != nil {
+		return err
+	}
+	if err := audit.RecordChanges(tx, auditActor(c), "car_brands", before, after); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// storeUpload - reads the multipart "file" field and stores it under prefix.
+// On failure it returns the HTTP status and a message safe to show the client.
+func storeUpload(c *fiber.Ctx, prefix string) (*media.Stored, int, error) {
+	fh, err := c.FormFile("file")
+	if err != nil {
+		return nil, 400, errors.New("file is required (multipart field \"file\")")
+	}
+	if fh.Size > media.MaxUploadBytes {
+		return nil, 413, media.ErrTooLarge
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		return nil, 400, errors.New("Failed to read upload")
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, media.MaxUploadBytes+1))
+	if err != nil {
+		return nil, 400, errors.New("Failed to read upload")
+	}
+
+	name, err := auth.RandomString(12)
+	if err != nil {
+		return nil, 500, errors.New("Failed to store image")
+	}
+
+	stored, err := media.Store(c.Context(), storage.Current(), prefix+"/"+name, data)
+	switch {
+	case err == nil:
+		return stored, 0, nil
+	case errors.Is(err, media.ErrTooLarge):
+		return nil, 413, err
+	case errors.Is(err, media.ErrUnsupportedType):
+		return nil, 415, err
+	case errors.Is(err, media.ErrTooManyPixels), errors.Is(err, media.ErrInvalidImage):
+		return nil, 400, err
+	}
+	log.Println("⚠️  Image storage failed:", err)
+	return nil, 500, errors.New("Failed to store image")
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"comparebuddy-backend/config"
+	"comparebuddy-backend/middleware"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type UpdateProfileRequest struct {
+	DisplayName *string `json:"display_name"`
+	Email       *string `json:"email"`
+	AvatarURL   *string `json:"avatar_url"`
+}
+
+// GetMe - GET /api/me
+func GetMe(c *fiber.Ctx) error {
+	return c.JSON(middleware.CurrentUser(c))
+}
+
+// UpdateMe - PUT /api/me
+// Only the fields present in the body are changed; an empty avatar_url clears it.
+func UpdateMe(c *fiber.Ctx) error {
+	user := middleware.CurrentUser(c)
+
+	var req UpdateProfileRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	sets := []string{}
+	args := []interface{}{}
+
+	if req.DisplayName != nil {
+		displayName, msg := normalizeDisplayName(*req.DisplayName)
+		if msg != "" {
+			return c.Status(400).JSON(fiber.Map{"error": msg})
+		}
+		sets = append(sets, "display_name = ?")
+		args = append(args, displayName)
+		user.DisplayName = displayName
+	}
+	if req.Email != nil {
+		email := strings.TrimSpace(*req.Email)
+		if email == "" || !strings.Contains(email, "@") {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid email address"})
+		}
+		sets = append(sets, "email = ?")
+		args = append(args, email)
+		user.Email = email
+	}
+	if req.AvatarURL != nil {
+		avatarURL := strings.TrimSpace(*req.AvatarURL)
+		if avatarURL == "" {
+			sets = append(sets, "avatar_url = NULL")
+		} else {
+			u, err := url.Parse(avatarURL)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return c.Status(400).JSON(fiber.Map{"error": "Avatar URL must be an http(s) URL"})
+			}
+			sets = append(sets, "avatar_url = ?")
+			args = append(args, avatarURL)
+		}
+		user.AvatarURL = avatarURL
+	}
+
+	if len(sets) == 0 {
+		return c.Status(400).JSON(fiber.Map{"error": "Nothing to update"})
+	}
+
+	args = append(args, user.ID)
+	_, err := config.DB.Exec("UPDATE users SET "+strings.Join(sets, ", ")+" WHERE id = ?", args...)
+	if err != nil {
+		if strings.Contains(err.Error(), "Duplicate entry") {
+			return c.Status(409).JSON(fiber.Map{"error": "Email already exists"})
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update profile"})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Profile updated",
+		"user":    user,
+	})
+}
+
+// normalizeDisplayName returns the trimmed name or a validation message (blank, over 100 characters)
+func normalizeDisplayName(name string) (string, string) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", "Display name cannot be empty"
+	}
+	if utf8.RuneCountInString(name) > 100 {
+		return "", "Display name is too long"
+	}
+	return name, ""
+}
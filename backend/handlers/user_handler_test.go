@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeDisplayName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+		err  bool
+	}{
+		{"  Somchai ", "Somchai", false},
+		{"   ", "", true},
+		{strings.Repeat("ก", 100), strings.Repeat("ก", 100), false},
+		{strings.Repeat("ก", 101), "", true},
+		{strings.Repeat("a", 101), "", true},
+	}
+	for _, tt := range tests {
+		got, msg := normalizeDisplayName(tt.name)
+		if got != tt.want || (msg != "") != tt.err {
+			t.Errorf("normalizeDisplayName(%q) = %q, %q", tt.name, got, msg)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"comparebuddy-backend/models"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// variantColumn - one selected column of the full variant spec, derived from
+// the db tag of a models.CarVariant field
+type variantColumn struct {
+	Key        string // json key, also the key in models.SpecFields
+	Table      string // table the column lives in
+	Name       string // column name inside Table
+	Expr       string // SQL expression in the variant query, e.g. v.range_km
+	FieldIndex int
+}
+
+// Aliases used by every full-spec variant query
+var variantTableAliases = map[string]string{
+	"v": "car_variants",
+	"m": "car_models",
+	"b": "car_brands",
+}
+
+// car_variants columns that are intentionally not part of models.CarVariant
+var variantUnmappedColumns = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+}
+
+var (
+	// variantFieldIndex - json key -> struct field index of models.CarVariant
+	variantFieldIndex map[string]int
+	// variantColumnList - db-tagged fields of models.CarVariant, in struct order
+	variantColumnList []variantColumn
+	// variantColumns - select list for the full spec query, matches variantColumnList
+	variantColumns string
+)
+
+func init() {
+	variantFieldIndex, variantColumnList = mapVariantStruct()
+
+	if err := checkSpecRegistry(); err != nil {
+		panic(err)
+	}
+
+	exprs := make([]string, len(variantColumnList))
+	for i, col := range variantColumnList {
+		exprs[i] = col.Expr
+	}
+	variantColumns = strings.Join(exprs, ", ")
+
+	filterColumnByKey = filterableColumns()
+	variantTable = variantCatalogTable()
+}
+
+func mapVariantStruct() (map[string]int, []variantColumn) {
+	index := map[string]int{}
+	var columns []variantColumn
+
+	t := reflect.TypeOf(models.CarVariant{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := strings.Split(field.Tag.Get("json"), ",")[0]
+		if key == "" || key == "-" {
+			continue
+		}
+		index[key] = i
+
+		tag := field.Tag.Get("db")
+		if tag == "" {
+			continue
+		}
+		alias, name := "v", tag
+		if dot := strings.IndexByte(tag, '.'); dot >= 0 {
+			alias, name = tag[:dot], tag[dot+1:]
+		}
+		table, ok := variantTableAliases[alias]
+		if !ok {
+			panic(fmt.Sprintf("models.CarVariant.%s: unknown table alias %q in db tag", field.Name, alias))
+		}
+		columns = append(columns, variantColumn{
+			Key:        key,
+			Table:      table,
+			Name:       name,
+			Expr:       alias + "." + name,
+			FieldIndex: i,
+		})
+	}
+	return index, columns
+}
+
+// checkSpecRegistry - models.SpecFields and the db-tagged fields of models.CarVariant
+// must describe exactly the same set of columns, with matching value types
+func checkSpecRegistry() error {
+	var problems []string
+
+	mapped := map[string]bool{}
+	t := reflect.TypeOf(models.CarVariant{})
+	for _, col := range variantColumnList {
+		mapped[col.Key] = true
+		spec, ok := models.SpecFieldByKey[col.Key]
+		if !ok {
+			problems = append(problems, col.Key+": db-tagged field missing from models.SpecFields")
+			continue
+		}
+		if !specTypeMatches(spec.Type, t.Field(col.FieldIndex).Type) {
+			problems = append(problems, fmt.Sprintf("%s: registry type %s does not match Go type %s", col.Key, spec.Type, t.Field(col.FieldIndex).Type))
+		}
+	}
+	for _, f := range models.SpecFields {
+		if !mapped[f.Key] {
+			problems = append(problems, f.Key+": in models.SpecFields but no CarVariant field has a db tag for it")
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("car spec registry out of sync:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// specTypeMatches - whether a registry value type fits the Go type of the struct field
+func specTypeMatches(specType string, t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch specType {
+	case models.SpecTypeInteger:
+		return t.Kind() == reflect.Int
+	case models.SpecTypeDecimal:
+		return t.Kind() == reflect.Float64
+	case models.SpecTypeBoolean:
+		return t.Kind() == reflect.Bool
+	case models.SpecTypeText, models.SpecTypeEnum:
+		return t.Kind() == reflect.String
+	}
+	return false
+}
+
+// schemaTypeMatches - whether a MySQL column type can hold a registry value type
+func schemaTypeMatches(specType, dataType, columnType string) bool {
+	isBool := strings.HasPrefix(columnType, "tinyint(1)") || dataType == "bit"
+	switch specType {
+	case models.SpecTypeInteger:
+		return !isBool && (dataType == "int" || dataType == "smallint" || dataType == "mediumint" || dataType == "bigint" || dataType == "tinyint")
+	case models.SpecTypeDecimal:
+		return dataType == "decimal" || dataType == "float" || dataType == "double"
+	case models.SpecTypeBoolean:
+		return isBool
+	case models.SpecTypeText:
+		return dataType == "varchar" || dataType == "char" || dataType == "text" || dataType == "mediumtext"
+	case models.SpecTypeEnum:
+		return dataType == "enum"
+	}
+	return false
+}
+
+func scanVariant(scanner interface{ Scan(...interface{}) error }) (models.CarVariant, error) {
+	return scanVariantColumns(scanner, variantColumnList)
+}
+
+// scanVariantColumns - scans a row selected with variantSelectList(cols)
+func scanVariantColumns(scanner interface{ Scan(...interface{}) error }, cols []variantColumn) (models.CarVariant, error) {
+	if cols == nil {
+		cols = variantColumnList
+	}
+
+	var v models.CarVariant
+	rv := reflect.ValueOf(&v).Elem()
+	dest := make([]interface{}, len(cols))
+	for i, col := range cols {
+		dest[i] = rv.Field(col.FieldIndex).Addr().Interface()
+	}
+	err := scanner.Scan(dest...)
+	return v, err
+}
+
+// CheckVariantSchema - compares models.CarVariant against information_schema and
+// returns every column that is missing, has an incompatible type, or exists in
+// car_variants without a struct field. Called once at startup.
+func CheckVariantSchema(db *sql.DB) error {
+	tables := []interface{}{}
+	for _, table := range variantTableAliases {
+		tables = append(tables, table)
+	}
+
+	placeholders := strings.Repeat("?,", len(tables))
+	placeholders = placeholders[:len(placeholders)-1]
+
+	rows, err := db.Query(
+		"SELECT TABLE_NAME, COLUMN_NAME, DATA_TYPE, COLUMN_TYPE FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME IN ("+placeholders+")",
+		tables...,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	type schemaColumn struct {
+		dataType   string
+		columnType string
+	}
+	schema := map[string]map[string]schemaColumn{}
+	for rows.Next() {
+		var table, name, dataType, columnType string
+		if err := rows.Scan(&table, &name, &dataType, &columnType); err != nil {
+			return err
+		}
+		if schema[table] == nil {
+			schema[table] = map[string]schemaColumn{}
+		}
+		schema[table][name] = schemaColumn{strings.ToLower(dataType), strings.ToLower(columnType)}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	var problems []string
+	mapped := map[string]bool{}
+	for _, col := range variantColumnList {
+		if col.Table == "car_variants" {
+			mapped[col.Name] = true
+		}
+		sc, ok := schema[col.Table][col.Name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s.%s: column does not exist (field %s)", col.Table, col.Name, col.Key))
+			continue
+		}
+		specType := models.SpecFieldByKey[col.Key].Type
+		if !schemaTypeMatches(specType, sc.dataType, sc.columnType) {
+			problems = append(problems, fmt.Sprintf("%s.%s: column type %s does not fit field %s (%s)", col.Table, col.Name, sc.columnType, col.Key, specType))
+		}
+	}
+
+	var unmapped []string
+	for name := range schema["car_variants"] {
+		if !mapped[name] && !variantUnmappedColumns[name] {
+			unmapped = append(unmapped, name)
+		}
+	}
+	sort.Strings(unmapped)
+	for _, name := range unmapped {
+		problems = append(problems, "car_variants."+name+": column has no models.CarVariant field")
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("models.CarVariant does not match the database schema:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"comparebuddy-backend/models"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Browse filter language, one query parameter per condition:
+//
+//	min_<key>=, max_<key>=   range on an integer / decimal spec field
+//	<key>=a,b                equality / in on an integer or enum spec field
+//	<key>=true|false         boolean spec field (features such as aeb, v2l)
+//	brand=3 | brand=BYD,MG   brand id or brand name
+//
+// <key> is a json key from models.SpecFields; anything else is rejected.
+
+// legacyFilterParams - older browse parameters kept working as aliases
+var legacyFilterParams = map[string]string{
+	"min_price":           "min_price_baht",
+	"max_price":           "max_price_baht",
+	"min_range":           "min_range_km",
+	"min_fuel_efficiency": "min_fuel_consumption_kml",
+}
+
+// variantFilter - WHERE conditions over the full-spec variant query (aliases v, m, b)
+type variantFilter struct {
+	Conditions []string
+	Args       []interface{}
+	// Applied - normalized filters, echoed back to clients
+	Applied map[string]interface{}
+}
+
+// SQL - the conditions as " AND ..." to append to an existing WHERE clause
+func (f variantFilter) SQL() string {
+	if len(f.Conditions) == 0 {
+		return ""
+	}
+	return " AND " + strings.Join(f.Conditions, " AND ")
+}
+
+// filterColumnByKey - json key -> column of every spec field that can be filtered on
+// (everything but free text); built in init() of variant_columns.go
+var filterColumnByKey map[string]variantColumn
+
+func filterableColumns() map[string]variantColumn {
+	cols := map[string]variantColumn{}
+	for _, col := range variantColumnList {
+		if models.SpecFieldByKey[col.Key].Type != models.SpecTypeText {
+			cols[col.Key] = col
+		}
+	}
+	return cols
+}
+
+// parseVariantFilters - validates params against the spec field whitelist and builds
+// the WHERE conditions. Keys listed in reserved (paging, sorting...) are skipped.
+func parseVariantFilters(params map[string]string, reserved ...string) (variantFilter, error) {
+	f := variantFilter{Applied: map[string]interface{}{}}
+
+	skip := map[string]bool{}
+	for _, k := range reserved {
+		skip[k] = true
+	}
+
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		if !skip[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, param := range keys {
+		raw := strings.TrimSpace(params[param])
+		if raw == "" {
+			continue
+		}
+		if alias, ok := legacyFilterParams[param]; ok {
+			param = alias
+		}
+		if err := f.add(param, raw); err != nil {
+			return f, err
+		}
+	}
+	return f, nil
+}
+
+func (f *variantFilter) add(param, raw string) error {
+	if len(splitList(raw)) == 0 {
+		return fmt.Errorf("%s must not be empty", param)
+	}
+
+	if param == "brand" {
+		values := splitList(raw)
+		ids := []interface{}{}
+		names := []interface{}{}
+		for _, s := range values {
+			if id, err := strconv.Atoi(s); err == nil {
+				ids = append(ids, id)
+			} else {
+				names = append(names, s)
+			}
+		}
+		var conds []string
+		if len(ids) > 0 {
+			conds = append(conds, "b.id IN ("+sqlPlaceholders(len(ids))+")")
+			f.Args = append(f.Args, ids...)
+		}
+		if len(names) > 0 {
+			conds = append(conds, "b.name IN ("+sqlPlaceholders(len(names))+")")
+			f.Args = append(f.Args, names...)
+		}
+		f.Conditions = append(f.Conditions, "("+strings.Join(conds, " OR ")+")")
+		f.Applied[param] = values
+		return nil
+	}
+
+	_, isKey := filterColumnByKey[param]
+	if !isKey && (strings.HasPrefix(param, "min_") || strings.HasPrefix(param, "max_")) {
+		key := param[4:]
+		col, ok := filterColumnByKey[key]
+		if !ok || !isNumericSpec(key) {
+			return fmt.Errorf("unknown filter: %s", param)
+		}
+		n, err := parseSpecNumber(key, raw)
+		if err != nil {
+			return fmt.Errorf("%s must be a number", param)
+		}
+		op := ">="
+		if param[:4] == "max_" {
+			op = "<="
+		}
+		f.Conditions = append(f.Conditions, col.Expr+" "+op+" ?")
+		f.Args = append(f.Args, n)
+		f.Applied[param] = n
+		return nil
+	}
+
+	col, ok := filterColumnByKey[param]
+	if !ok {
+		return fmt.Errorf("unknown filter: %s", param)
+	}
+	spec := models.SpecFieldByKey[param]
+	switch spec.Type {
+	case models.SpecTypeBoolean:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%s must be true or false", param)
+		}
+		f.Conditions = append(f.Conditions, col.Expr+" = ?")
+		f.Args = append(f.Args, b)
+		f.Applied[param] = b
+	case models.SpecTypeEnum:
+		values, err := enumValues(param, raw, spec.Options)
+		if err != nil {
+			return err
+		}
+		f.in(col.Expr, values)
+		f.Applied[param] = values
+	default:
+		var values []interface{}
+		for _, s := range splitList(raw) {
+			n, err := parseSpecNumber(param, s)
+			if err != nil {
+				return fmt.Errorf("%s must be a number or a comma-separated list of numbers", param)
+			}
+			values = append(values, n)
+		}
+		f.Conditions = append(f.Conditions, col.Expr+" IN ("+sqlPlaceholders(len(values))+")")
+		f.Args = append(f.Args, values...)
+		f.Applied[param] = values
+	}
+	return nil
+}
+
+func (f *variantFilter) in(expr string, values []string) {
+	f.Conditions = append(f.Conditions, expr+" IN ("+sqlPlaceholders(len(values))+")")
+	for _, v := range values {
+		f.Args = append(f.Args, v)
+	}
+}
+
+func isNumericSpec(key string) bool {
+	t := models.SpecFieldByKey[key].Type
+	return t == models.SpecTypeInteger || t == models.SpecTypeDecimal
+}
+
+func parseSpecNumber(key, s string) (interface{}, error) {
+	if models.SpecFieldByKey[key].Type == models.SpecTypeInteger {
+		return strconv.Atoi(s)
+	}
+	x, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, err
+	}
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		return nil, fmt.Errorf("%s is not a finite number", s)
+	}
+	return x, nil
+}
+
+// enumValues - splits a comma-separated list and maps each value onto its
+// canonical option, ignoring case
+func enumValues(param, raw string, options []string) ([]string, error) {
+	var values []string
+	for _, s := range splitList(raw) {
+		match := ""
+		for _, opt := range options {
+			if strings.EqualFold(s, opt) {
+				match = opt
+				break
+			}
+		}
+		if match == "" {
+			return nil, fmt.Errorf("%s must be one of: %s", param, strings.Join(options, ", "))
+		}
+		values = append(values, match)
+	}
+	return values, nil
+}
+
+func splitList(raw string) []string {
+	var out []string
+	for _, s := range strings.Split(raw, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func sqlPlaceholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
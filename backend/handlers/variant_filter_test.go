@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseVariantFilters(t *testing.T) {
+	tests := []struct {
+		name   string
+		params map[string]string
+		sql    string
+		args   []interface{}
+	}{
+		{
+			name:   "no filters",
+			params: map[string]string{"limit": "20", "sort": "price", "airbags": " "},
+		},
+		{
+			name:   "decimal range",
+			params: map[string]string{"min_price_baht": "800000", "max_price_baht": "1200000.50"},
+			sql:    " AND v.price_baht <= ? AND v.price_baht >= ?",
+			args:   []interface{}{1200000.5, 800000.0},
+		},
+		{
+			name:   "legacy alias",
+			params: map[string]string{"min_range": "400"},
+			sql:    " AND v.range_km >= ?",
+			args:   []interface{}{400},
+		},
+		{
+			name:   "integer list",
+			params: map[string]string{"airbags": "6, 7,"},
+			sql:    " AND v.airbags IN (?,?)",
+			args:   []interface{}{6, 7},
+		},
+		{
+			name:   "enum is case-insensitive and canonicalized",
+			params: map[string]string{"body_type": "SUV,Sedan", "drive_type": "awd"},
+			sql:    " AND m.body_type IN (?,?) AND v.drive_type IN (?)",
+			args:   []interface{}{"suv", "sedan", "AWD"},
+		},
+		{
+			name:   "boolean",
+			params: map[string]string{"v2l": "true"},
+			sql:    " AND v.v2l = ?",
+			args:   []interface{}{true},
+		},
+		{
+			name:   "brand ids and names",
+			params: map[string]string{"brand": "3,BYD,MG"},
+			sql:    " AND (b.id IN (?) OR b.name IN (?,?))",
+			args:   []interface{}{3, "BYD", "MG"},
+		},
+	}
+	for _, tt := range tests {
+		f, err := parseVariantFilters(tt.params, "limit", "sort")
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		if got := f.SQL(); got != tt.sql || !reflect.DeepEqual(f.Args, tt.args) {
+			t.Errorf("%s: SQL() = %q %v, want %q %v", tt.name, got, f.Args, tt.sql, tt.args)
+		}
+	}
+}
+
+func TestParseVariantFiltersRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		params map[string]string
+		want   string
+	}{
+		{"unknown key", map[string]string{"colour": "red"}, "unknown filter: colour"},
+		{"range on text field", map[string]string{"min_battery_type": "1"}, "unknown filter: min_battery_type"},
+		{"range on enum", map[string]string{"max_body_type": "1"}, "unknown filter: max_body_type"},
+		{"equality on text field", map[string]string{"battery_type": "LFP"}, "unknown filter: battery_type"},
+		{"not a number", map[string]string{"min_price_baht": "cheap"}, "min_price_baht must be a number"},
+		{"NaN", map[string]string{"min_price_baht": "NaN"}, "min_price_baht must be a number"},
+		{"Inf", map[string]string{"max_price_baht": "Inf"}, "max_price_baht must be a number"},
+		{"NaN in a list", map[string]string{"battery_capacity_kwh": "60,nan"}, "battery_capacity_kwh must be a number or a comma-separated list of numbers"},
+		{"decimal for integer", map[string]string{"min_range_km": "400.5"}, "min_range_km must be a number"},
+		{"bad list", map[string]string{"airbags": "6,many"}, "airbags must be a number or a comma-separated list of numbers"},
+		{"bad boolean", map[string]string{"v2l": "maybe"}, "v2l must be true or false"},
+		{"bad enum", map[string]string{"drive_type": "2WD"}, "drive_type must be one of: FWD, RWD, AWD, 4WD"},
+		{"only commas", map[string]string{"brand": ",,"}, "brand must not be empty"},
+	}
+	for _, tt := range tests {
+		_, err := parseVariantFilters(tt.params)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
@@ -1,10 +1,22 @@
 package main
 
 import (
+	"comparebuddy-backend/alerts"
+	"comparebuddy-backend/auth"
 	"comparebuddy-backend/config"
+	"comparebuddy-backend/handlers"
+	"comparebuddy-backend/media"
+	"comparebuddy-backend/middleware"
+	"comparebuddy-backend/notify"
+	"comparebuddy-backend/rbac"
 	"comparebuddy-backend/routes"
+	"comparebuddy-backend/storage"
+	"comparebuddy-backend/tco"
+	"context"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
 	
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -18,27 +30,78 @@ func main() {
 	config.ConnectDB()
 	defer config.DB.Close()
 	
+	// Fail fast if models.CarVariant and car_variants have drifted apart
+	if err := handlers.CheckVariantSchema(config.DB); err != nil {
+		log.Fatal("❌ Schema check failed: ", err)
+	}
+	
+	// Load token signing secret
+	auth.Init()
+	
+	// Grant the first admin from BOOTSTRAP_ADMIN
+	rbac.Bootstrap()
+	
+	// Load TCO insurance / tax / maintenance tables
+	tco.Init()
+	
+	// Select where uploaded images are stored
+	storage.Init()
+	
+	// Stop the alert evaluator and the server on SIGINT / SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	
+	// Evaluate price-drop alerts in the background
+	alertsDone := make(chan struct{})
+	go func() {
+		alerts.Run(ctx, notify.FromEnv(), alerts.Interval())
+		close(alertsDone)
+	}()
+	
 	// Create Fiber app
 	app := fiber.New(fiber.Config{
 		AppName: "CompareBuddy API v1.0",
+		// Room for an image upload plus the multipart framing; other routes
+		// are held to the default by middleware.BodyLimit (see routes)
+		BodyLimit: media.MaxUploadBytes + 1<<20,
 	})
 	
+	// Server-generated X-Request-ID on every response, recorded in the audit log
+	app.Use(middleware.RequestID())
+	
 	// CORS middleware
 	app.Use(cors.New(cors.Config{
 		AllowOrigins: "*",
-		AllowHeaders: "Origin, Content-Type, Accept",
-		AllowMethods: "GET, POST, PUT, DELETE",
+		AllowHeaders: "Origin, Content-Type, Accept, Authorization",
+		ExposeHeaders: "X-Request-ID",
+		AllowMethods: "GET, POST, PUT, PATCH, DELETE",
 	}))
 	
 	// Setup routes
 	routes.SetupRoutes(app)
 	
+	// Serve uploaded images when they are stored locally
+	if local, ok := storage.Current().(*storage.Local); ok {
+		app.Static("/media", local.Dir)
+	}
+	
 	// Start server
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
 	}
 	
+	go func() {
+		<-ctx.Done()
+		app.Shutdown()
+	}()
+	
 	log.Printf("🚀 Go Backend running on http://localhost:%s\n", port)
-	log.Fatal(app.Listen(":" + port))
+	if err := app.Listen(":" + port); err != nil {
+		log.Fatal(err)
+	}
+	
+	// Let a running evaluation finish before the database is closed
+	<-alertsDone
+	log.Println("👋 Server stopped")
 }
@@ -0,0 +1,162 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"net/http"
+
+	// Decoders for image.Decode
+	_ "image/gif"
+	_ "image/png"
+)
+
+const (
+	// MaxUploadBytes - largest accepted upload
+	MaxUploadBytes = 10 << 20
+	// maxPixels - guards against decompression bombs: a tiny file can declare a huge canvas
+	maxPixels = 40_000_000
+	// jpegQuality - for generated thumbnails
+	jpegQuality = 82
+)
+
+var (
+	ErrTooLarge        = fmt.Errorf("image is larger than %d MB", MaxUploadBytes>>20)
+	ErrUnsupportedType = errors.New("image must be JPEG, PNG or GIF")
+	ErrTooManyPixels   = errors.New("image dimensions are too large")
+	ErrInvalidImage    = errors.New("invalid image")
+)
+
+// ContentTypes - accepted uploads by sniffed content type, with the file extension to store them under
+var ContentTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+// ThumbnailSize - a generated variant no wider than Width pixels
+type ThumbnailSize struct {
+	Name  string
+	Width int
+}
+
+// ThumbnailSizes - generated for every upload that is wider than the size
+var ThumbnailSizes = []ThumbnailSize{
+	{"small", 160},
+	{"medium", 480},
+	{"large", 1080},
+}
+
+// Decoded - a validated upload
+type Decoded struct {
+	Image       image.Image
+	ContentType string
+	Ext         string
+	Width       int
+	Height      int
+}
+
+// Decode - checks size, type (sniffed from the bytes, not the client's
+// Content-Type) and dimensions, then decodes the image
+func Decode(data []byte) (*Decoded, error) {
+	if len(data) > MaxUploadBytes {
+		return nil, ErrTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	ext, ok := ContentTypes[contentType]
+	if !ok {
+		return nil, ErrUnsupportedType
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxPixels {
+		return nil, ErrTooManyPixels
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	b := img.Bounds()
+	return &Decoded{Image: img, ContentType: contentType, Ext: ext, Width: b.Dx(), Height: b.Dy()}, nil
+}
+
+// Resize - img scaled down to width, keeping the aspect ratio. Each output
+// pixel is the average of the source pixels it covers, which is sharp enough
+// for thumbnails without pulling in an imaging library. Other image types are
+// converted to RGBA first, so pass an *image.RGBA when resizing one image
+// several times.
+func Resize(img image.Image, width int) *image.RGBA {
+	src := toRGBA(img)
+	sw, sh := src.Bounds().Dx(), src.Bounds().Dy()
+	if width >= sw {
+		return src
+	}
+	height := sh * width / sw
+	if height < 1 {
+		height = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0, y1 := y*sh/height, (y+1)*sh/height
+		if y1 == y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < width; x++ {
+			x0, x1 := x*sw/width, (x+1)*sw/width
+			if x1 == x0 {
+				x1 = x0 + 1
+			}
+			var r, g, b, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				off := src.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					r += uint32(src.Pix[off])
+					g += uint32(src.Pix[off+1])
+					b += uint32(src.Pix[off+2])
+					a += uint32(src.Pix[off+3])
+					off += 4
+					n++
+				}
+			}
+			off := dst.PixOffset(x, y)
+			dst.Pix[off] = uint8(r / n)
+			dst.Pix[off+1] = uint8(g / n)
+			dst.Pix[off+2] = uint8(b / n)
+			dst.Pix[off+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// EncodeJPEG - thumbnails are always JPEG; transparent areas become white
+func EncodeJPEG(img image.Image) ([]byte, error) {
+	bg := image.NewRGBA(img.Bounds())
+	draw.Draw(bg, bg.Bounds(), &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+	draw.Draw(bg, bg.Bounds(), img, img.Bounds().Min, draw.Over)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, bg, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// toRGBA - img as an RGBA image with its origin at 0,0
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Bounds().Min == (image.Point{}) {
+		return rgba
+	}
+	b := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, b.Min, draw.Src)
+	return rgba
+}
@@ -0,0 +1,133 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func solid(w, h int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestResize(t *testing.T) {
+	tests := []struct {
+		w, h, width  int
+		wantW, wantH int
+	}{
+		{1600, 900, 480, 480, 270},
+		{300, 200, 480, 300, 200},
+		{2000, 1, 160, 160, 1},
+	}
+	for _, tt := range tests {
+		got := Resize(solid(tt.w, tt.h, color.White), tt.width).Bounds()
+		if got.Dx() != tt.wantW || got.Dy() != tt.wantH {
+			t.Errorf("Resize(%dx%d, %d) = %dx%d, want %dx%d", tt.w, tt.h, tt.width, got.Dx(), got.Dy(), tt.wantW, tt.wantH)
+		}
+	}
+}
+
+func TestResizeAverages(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	src.Set(0, 0, color.RGBA{0, 0, 0, 255})
+	src.Set(1, 0, color.RGBA{200, 100, 50, 255})
+	got := Resize(src, 1).RGBAAt(0, 0)
+	if want := (color.RGBA{100, 50, 25, 255}); got != want {
+		t.Errorf("Resize pixel = %v, want %v", got, want)
+	}
+}
+
+func TestBlurhash(t *testing.T) {
+	// 4x3 components: 1 size + 1 max AC + 4 DC + 11 AC * 2 characters
+	const wantLen = 28
+
+	tests := []struct {
+		name string
+		img  image.Image
+	}{
+		{"solid", solid(64, 48, color.RGBA{120, 40, 200, 255})},
+		{"gradient", func() image.Image {
+			img := image.NewRGBA(image.Rect(0, 0, 120, 80))
+			for y := 0; y < 80; y++ {
+				for x := 0; x < 120; x++ {
+					img.Set(x, y, color.RGBA{uint8(x * 2), uint8(y * 3), 128, 255})
+				}
+			}
+			return img
+		}()},
+	}
+	for _, tt := range tests {
+		hash := Blurhash(tt.img)
+		if len(hash) != wantLen {
+			t.Errorf("%s: Blurhash = %q, want %d characters", tt.name, hash, wantLen)
+		}
+		if again := Blurhash(tt.img); again != hash {
+			t.Errorf("%s: Blurhash not deterministic: %q then %q", tt.name, hash, again)
+		}
+		if hash[0] != base83Chars[(blurhashX-1)+(blurhashY-1)*9] {
+			t.Errorf("%s: Blurhash = %q, size flag does not encode %dx%d", tt.name, hash, blurhashX, blurhashY)
+		}
+	}
+
+	// The DC component is the average colour: the solid colour itself
+	dc := 0
+	for _, c := range Blurhash(tests[0].img)[2:6] {
+		dc = dc*83 + strings.IndexRune(base83Chars, c)
+	}
+	if want := 120<<16 | 40<<8 | 200; dc != want {
+		t.Errorf("solid: DC = %06x, want %06x", dc, want)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, solid(40, 30, color.Black)); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := Decode(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.ContentType != "image/png" || d.Ext != ".png" || d.Width != 40 || d.Height != 30 {
+		t.Errorf("Decode = %s %s %dx%d, want image/png .png 40x30", d.ContentType, d.Ext, d.Width, d.Height)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"text", []byte("not an image"), ErrUnsupportedType},
+		{"truncated", buf.Bytes()[:20], ErrInvalidImage},
+		{"too large", make([]byte, MaxUploadBytes+1), ErrTooLarge},
+	}
+	for _, tt := range tests {
+		if _, err := Decode(tt.data); !errors.Is(err, tt.want) {
+			t.Errorf("%s: Decode error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestThumbnailKey(t *testing.T) {
+	tests := []struct{ key, size, want string }{
+		{"variants/12/abc.png", "small", "variants/12/abc_small.jpg"},
+		{"brands/3/logo", "large", "brands/3/logo_large.jpg"},
+		{"a.b/c", "medium", "a.b/c_medium.jpg"},
+	}
+	for _, tt := range tests {
+		if got := ThumbnailKey(tt.key, tt.size); got != tt.want {
+			t.Errorf("ThumbnailKey(%q, %q) = %q, want %q", tt.key, tt.size, got, tt.want)
+		}
+	}
+}
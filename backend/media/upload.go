@@ -0,0 +1,98 @@
+package media
+
+import (
+	"comparebuddy-backend/storage"
+	"context"
+	"strings"
+)
+
+// Stored - an uploaded image and its thumbnails, as saved to a storage.Store
+type Stored struct {
+	Key        string
+	URL        string
+	Width      int
+	Height     int
+	Blurhash   string
+	Thumbnails map[string]string
+}
+
+// Store - validates data, then saves the original under key+ext and one JPEG per
+// ThumbnailSize narrower than the image under ThumbnailKey. If any write fails
+// the files written so far are removed again.
+func Store(ctx context.Context, store storage.Store, key string, data []byte) (*Stored, error) {
+	img, err := Decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Converted once: Blurhash and every thumbnail resize from the same RGBA,
+	// which is ~160 MB for the largest accepted image
+	src := toRGBA(img.Image)
+
+	out := &Stored{
+		Key:        key + img.Ext,
+		Width:      img.Width,
+		Height:     img.Height,
+		Blurhash:   Blurhash(src),
+		Thumbnails: map[string]string{},
+	}
+
+	var written []string
+	cleanup := func() {
+		for _, k := range written {
+			store.Delete(ctx, k)
+		}
+	}
+
+	if out.URL, err = store.Put(ctx, out.Key, data, img.ContentType); err != nil {
+		return nil, err
+	}
+	written = append(written, out.Key)
+
+	for _, size := range ThumbnailSizes {
+		if size.Width >= img.Width {
+			continue
+		}
+		thumb, err := EncodeJPEG(Resize(src, size.Width))
+		if err != nil {
+			cleanup()
+			return nil, err
+		}
+		thumbKey := ThumbnailKey(out.Key, size.Name)
+		url, err := store.Put(ctx, thumbKey, thumb, "image/jpeg")
+		if err != nil {
+			cleanup()
+			return nil, err
+		}
+		written = append(written, thumbKey)
+		out.Thumbnails[size.Name] = url
+	}
+	return out, nil
+}
+
+// Remove - deletes an image stored by Store together with its thumbnails
+func Remove(ctx context.Context, store storage.Store, key string) error {
+	var firstErr error
+	for _, k := range append([]string{key}, thumbnailKeys(key)...) {
+		if err := store.Delete(ctx, k); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// ThumbnailKey - "variants/12/abc.png" -> "variants/12/abc_small.jpg"
+func ThumbnailKey(key, size string) string {
+	if dot := strings.LastIndexByte(key, '.'); dot > strings.LastIndexByte(key, '/') {
+		key = key[:dot]
+	}
+	return key + "_" + size + ".jpg"
+}
+
+func thumbnailKeys(key string) []string {
+	keys := make([]string, len(ThumbnailSizes))
+	for i, size := range ThumbnailSizes {
+		keys[i] = ThumbnailKey(key, size.Name)
+	}
+	return keys
+}
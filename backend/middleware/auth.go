@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"comparebuddy-backend/auth"
+	"comparebuddy-backend/config"
+	"comparebuddy-backend/models"
+	"database/sql"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const userLocalsKey = "user"
+
+// RequireAuth - rejects requests without a valid bearer access token
+func RequireAuth() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token := bearerToken(c)
+		if token == "" {
+			return Unauthorized(c, "unauthorized", "Authentication required")
+		}
+
+		user, code, err := authenticate(token)
+		if err != nil {
+			if code == "" {
+				return c.Status(500).JSON(fiber.Map{"error": "Failed to load user"})
+			}
+			return Unauthorized(c, code, "Invalid or expired access token")
+		}
+
+		c.Locals(userLocalsKey, user)
+		return c.Next()
+	}
+}
+
+// OptionalAuth - loads the user when a valid token is sent, otherwise continues anonymously
+func OptionalAuth() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if token := bearerToken(c); token != "" {
+			if user, _, err := authenticate(token); err == nil {
+				c.Locals(userLocalsKey, user)
+			}
+		}
+		return c.Next()
+	}
+}
+
+// CurrentUser - the signed-in user, or nil for anonymous requests
+func CurrentUser(c *fiber.Ctx) *models.User {
+	user, _ := c.Locals(userLocalsKey).(*models.User)
+	return user
+}
+
+// Unauthorized - the 401 body shared by every protected route.
+// code is "unauthorized" or "token_expired" so clients know when to refresh.
+func Unauthorized(c *fiber.Ctx, code, message string) error {
+	return c.Status(401).JSON(fiber.Map{"error": message, "code": code})
+}
+
+func bearerToken(c *fiber.Ctx) string {
+	header := c.Get(fiber.HeaderAuthorization)
+	if len(header) < 7 || !strings.EqualFold(header[:7], "Bearer ") {
+		return ""
+	}
+	return strings.TrimSpace(header[7:])
+}
+
+// authenticate returns the token's user; on failure code is the 401 code to
+// report, or empty when the failure was a server error.
+func authenticate(token string) (*models.User, string, error) {
+	claims, err := auth.ParseAccessToken(token)
+	if err == auth.ErrExpiredToken {
+		return nil, "token_expired", err
+	}
+	if err != nil {
+		return nil, "unauthorized", err
+	}
+
+	userID, err := claims.UserID()
+	if err != nil {
+		return nil, "unauthorized", err
+	}
+
+	var user models.User
+	err = config.DB.QueryRow(
+		"SELECT id, username, email, display_name, COALESCE(google_id, ''), COALESCE(avatar_url, ''), created_at FROM users WHERE id = ?",
+		userID,
+	).Scan(&user.ID, &user.Username, &user.Email, &user.DisplayName, &user.GoogleID, &user.AvatarURL, &user.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, "unauthorized", err
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &user, "", nil
+}
@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// BodyLimit - rejects request bodies larger than limit bytes with 413. The
+// server-wide fiber.Config.BodyLimit is sized for image uploads; this keeps
+// the routes registered after it at limit.
+func BodyLimit(limit int) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if len(c.Body()) > limit {
+			return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{"error": "Request body too large"})
+		}
+		return c.Next()
+	}
+}
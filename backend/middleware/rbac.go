@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"comparebuddy-backend/models"
+	"comparebuddy-backend/rbac"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequirePermission - only lets through users whose roles grant every one of
+// the given permissions (rbac.Perm*). Must run after RequireAuth.
+func RequirePermission(permissions ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		user := CurrentUser(c)
+		if user == nil {
+			return Unauthorized(c, "unauthorized", "Authentication required")
+		}
+		if err := LoadAccess(user); err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to load permissions"})
+		}
+		for _, p := range permissions {
+			if !hasPermission(user.Permissions, p) {
+				return c.Status(403).JSON(fiber.Map{"error": "Permission required", "permission": p})
+			}
+		}
+		return c.Next()
+	}
+}
+
+// LoadAccess - fills user.Roles and user.Permissions unless already loaded.
+// Authentication does not load them, so only the routes that need them pay
+// for the query.
+func LoadAccess(user *models.User) error {
+	if user.Roles != nil {
+		return nil
+	}
+	roles, permissions, err := rbac.Access(user.ID)
+	if err != nil {
+		return err
+	}
+	user.Roles, user.Permissions = roles, permissions
+	return nil
+}
+
+func hasPermission(granted []string, permission string) bool {
+	for _, p := range granted {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
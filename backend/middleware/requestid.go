@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+const requestIDLocalsKey = "requestid"
+
+// RequestID - gives every request a server-generated ID, returned in the
+// X-Request-ID response header. An X-Request-ID sent by the client is ignored:
+// the ID is recorded in the audit log, so the client must not choose it.
+func RequestID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := utils.UUIDv4()
+		c.Set(fiber.HeaderXRequestID, id)
+		c.Locals(requestIDLocalsKey, id)
+		return c.Next()
+	}
+}
+
+// CurrentRequestID - the ID assigned by RequestID, or "" outside it
+func CurrentRequestID(c *fiber.Ctx) string {
+	id, _ := c.Locals(requestIDLocalsKey).(string)
+	return id
+}
@@ -0,0 +1,34 @@
+-- =============================================
+-- CompareBuddy: Audit log of catalog changes
+-- =============================================
+-- One row per written row of car_brands, car_models, car_variants, items and
+-- categories. changes holds only the columns that changed:
+-- {"price_baht": {"before": 1199000, "after": 1099000}}. request_id is the
+-- server-generated X-Request-ID of the API request (a UUID; the client's own
+-- header is ignored), to group the rows one request wrote.
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    table_name VARCHAR(64) NOT NULL,
+    row_id BIGINT NOT NULL,
+    action ENUM('create','update','delete') NOT NULL,
+    user_id INT NULL,
+    request_id VARCHAR(64) NULL,
+    changes JSON NOT NULL,
+    created_at TIMESTAMP(3) DEFAULT CURRENT_TIMESTAMP(3)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+INSERT IGNORE INTO permissions (name, description) VALUES
+    ('audit.read', 'Read the audit log of catalog changes');
+
+INSERT IGNORE INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r JOIN permissions p
+WHERE r.name = 'admin' AND p.name = 'audit.read';
+
+-- =============================================
+-- INDEXES
+-- =============================================
+CREATE INDEX idx_audit_log_row ON audit_log(table_name, row_id, created_at);
+CREATE INDEX idx_audit_log_user ON audit_log(user_id, created_at);
+CREATE INDEX idx_audit_log_request ON audit_log(request_id);
+CREATE INDEX idx_audit_log_created ON audit_log(created_at);
@@ -0,0 +1,21 @@
+-- =============================================
+-- CompareBuddy: Auth / Session Tables
+-- =============================================
+
+-- 1. refresh_tokens (only the SHA-256 hash of each token is stored)
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    expires_at DATETIME NOT NULL,
+    revoked_at DATETIME NULL,
+    replaced_by INT NULL,
+    user_agent VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY unique_token_hash (token_hash)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- =============================================
+-- INDEXES
+-- =============================================
+CREATE INDEX idx_refresh_tokens_user ON refresh_tokens(user_id);
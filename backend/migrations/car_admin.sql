@@ -0,0 +1,8 @@
+-- =============================================
+-- CompareBuddy: Admin catalog editing
+-- =============================================
+-- Brands, models and variants are never deleted through the API, only marked
+-- discontinued. Brands had no status yet.
+
+ALTER TABLE car_brands
+    ADD COLUMN status ENUM('active','discontinued') NOT NULL DEFAULT 'active' AFTER logo_storage_key;
@@ -0,0 +1,23 @@
+-- =============================================
+-- CompareBuddy: Uploaded car images
+-- =============================================
+-- Images uploaded through the API keep their storage key (to delete the stored
+-- files later), dimensions, blurhash placeholder and resized thumbnails
+-- ({"small": url, "medium": url, ...}). Rows added by hand leave these NULL.
+
+ALTER TABLE car_images
+    ADD COLUMN storage_key VARCHAR(500) NULL AFTER image_url,
+    ADD COLUMN width INT NULL AFTER sort_order,
+    ADD COLUMN height INT NULL AFTER width,
+    ADD COLUMN blurhash VARCHAR(64) NULL AFTER height,
+    ADD COLUMN thumbnails JSON NULL AFTER blurhash,
+    ADD COLUMN uploaded_by INT NULL AFTER thumbnails,
+    ADD COLUMN created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP AFTER uploaded_by;
+
+ALTER TABLE car_brands
+    ADD COLUMN logo_storage_key VARCHAR(500) NULL AFTER logo_url;
+
+-- =============================================
+-- INDEXES
+-- =============================================
+CREATE INDEX idx_car_images_variant_order ON car_images (variant_id, image_type, sort_order);
@@ -0,0 +1,9 @@
+-- =============================================
+-- CompareBuddy: Public share links for user_comparisons
+-- =============================================
+
+ALTER TABLE user_comparisons
+    ADD COLUMN share_slug VARCHAR(32) NULL AFTER sort_order,
+    ADD COLUMN share_expires_at DATETIME NULL AFTER share_slug,
+    ADD COLUMN view_count INT NOT NULL DEFAULT 0 AFTER share_expires_at,
+    ADD UNIQUE KEY unique_share_slug (share_slug);
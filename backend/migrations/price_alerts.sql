@@ -0,0 +1,21 @@
+-- =============================================
+-- CompareBuddy: Price-drop alerts
+-- =============================================
+-- One alert per user and variant. The evaluator notifies when the price is at
+-- or below threshold_baht, then again only if it drops further; once the price
+-- goes back above the threshold last_notified_price is cleared.
+
+CREATE TABLE IF NOT EXISTS price_alerts (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    variant_id INT NOT NULL,
+    threshold_baht DECIMAL(12,2) NOT NULL,
+    active TINYINT(1) NOT NULL DEFAULT 1,
+    last_notified_price DECIMAL(12,2) NULL,
+    last_notified_at DATETIME NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY unique_alert (user_id, variant_id),
+    INDEX idx_price_alerts_variant (variant_id),
+    FOREIGN KEY (variant_id) REFERENCES car_variants(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
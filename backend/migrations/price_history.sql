@@ -0,0 +1,38 @@
+-- =============================================
+-- CompareBuddy: Price history for car_variants
+-- =============================================
+-- Every change of car_variants.price_baht is recorded by triggers, whatever
+-- writes it (admin API, imports, manual SQL).
+
+CREATE TABLE IF NOT EXISTS car_variant_prices (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    variant_id INT NOT NULL,
+    price_baht DECIMAL(12,2),
+    previous_price_baht DECIMAL(12,2),
+    changed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_variant_prices_variant (variant_id, changed_at),
+    FOREIGN KEY (variant_id) REFERENCES car_variants(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Starting point: the current price of every existing variant
+INSERT INTO car_variant_prices (variant_id, price_baht, previous_price_baht, changed_at)
+SELECT id, price_baht, NULL, COALESCE(updated_at, created_at)
+FROM car_variants v
+WHERE price_baht IS NOT NULL
+  AND NOT EXISTS (SELECT 1 FROM car_variant_prices p WHERE p.variant_id = v.id);
+
+DROP TRIGGER IF EXISTS trg_car_variants_price_insert;
+CREATE TRIGGER trg_car_variants_price_insert
+AFTER INSERT ON car_variants
+FOR EACH ROW
+    INSERT INTO car_variant_prices (variant_id, price_baht, previous_price_baht)
+    SELECT NEW.id, NEW.price_baht, NULL FROM DUAL
+    WHERE NEW.price_baht IS NOT NULL;
+
+DROP TRIGGER IF EXISTS trg_car_variants_price_update;
+CREATE TRIGGER trg_car_variants_price_update
+AFTER UPDATE ON car_variants
+FOR EACH ROW
+    INSERT INTO car_variant_prices (variant_id, price_baht, previous_price_baht)
+    SELECT NEW.id, NEW.price_baht, OLD.price_baht FROM DUAL
+    WHERE NOT (OLD.price_baht <=> NEW.price_baht);
@@ -0,0 +1,60 @@
+-- =============================================
+-- CompareBuddy: Roles and permissions
+-- =============================================
+-- Every account implicitly has the "user" role; user_roles only stores the
+-- roles granted on top of it. The first admin is granted at startup from
+-- BOOTSTRAP_ADMIN, the email of an existing account (see rbac.Bootstrap).
+
+CREATE TABLE IF NOT EXISTS roles (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(50) NOT NULL,
+    description VARCHAR(255),
+    UNIQUE KEY unique_role_name (name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS permissions (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description VARCHAR(255),
+    UNIQUE KEY unique_permission_name (name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id INT NOT NULL,
+    permission_id INT NOT NULL,
+    PRIMARY KEY (role_id, permission_id),
+    FOREIGN KEY (role_id) REFERENCES roles(id) ON DELETE CASCADE,
+    FOREIGN KEY (permission_id) REFERENCES permissions(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id INT NOT NULL,
+    role_id INT NOT NULL,
+    granted_by INT NULL,
+    granted_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, role_id),
+    FOREIGN KEY (role_id) REFERENCES roles(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- =============================================
+-- SEED
+-- =============================================
+INSERT IGNORE INTO roles (name, description) VALUES
+    ('user', 'Every signed-in account'),
+    ('editor', 'Edits the car catalog and uploads media'),
+    ('admin', 'Everything, including granting roles');
+
+INSERT IGNORE INTO permissions (name, description) VALUES
+    ('catalog.write', 'Create, edit and discontinue brands, models and variants'),
+    ('media.upload', 'Upload and manage car images and brand logos'),
+    ('roles.manage', 'Grant and revoke roles');
+
+INSERT IGNORE INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r JOIN permissions p
+WHERE (r.name = 'editor' AND p.name IN ('catalog.write', 'media.upload'))
+   OR r.name = 'admin';
+
+-- =============================================
+-- INDEXES
+-- =============================================
+CREATE INDEX idx_user_roles_role ON user_roles(role_id);
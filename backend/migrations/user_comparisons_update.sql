@@ -0,0 +1,12 @@
+-- =============================================
+-- CompareBuddy: Saved comparisons (user_comparisons)
+-- =============================================
+
+ALTER TABLE user_comparisons
+    ADD COLUMN sort_order INT NOT NULL DEFAULT 0 AFTER title,
+    ADD COLUMN updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP AFTER created_at;
+
+-- =============================================
+-- INDEXES
+-- =============================================
+CREATE INDEX idx_user_comparisons_user ON user_comparisons(user_id, sort_order);
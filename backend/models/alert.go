@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// PriceAlert - notify the user when the variant's price is at or below ThresholdBaht
+type PriceAlert struct {
+	ID                int        `json:"id"`
+	UserID            int        `json:"user_id"`
+	VariantID         int        `json:"variant_id"`
+	ThresholdBaht     float64    `json:"threshold_baht"`
+	Active            bool       `json:"active"`
+	LastNotifiedPrice *float64   `json:"last_notified_price"`
+	LastNotifiedAt    *time.Time `json:"last_notified_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+
+	// Joined fields
+	VariantName string   `json:"variant_name"`
+	BrandName   string   `json:"brand_name"`
+	ModelName   string   `json:"model_name"`
+	PriceBaht   *float64 `json:"price_baht"`
+}
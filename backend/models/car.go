@@ -6,6 +6,7 @@ type CarBrand struct {
 	NameTh  *string `json:"name_th"`
 	Country *string `json:"country"`
 	LogoURL *string `json:"logo_url"`
+	Status  string  `json:"status"`
 }
 
 type CarBrandWithModels struct {
@@ -28,7 +29,7 @@ type CarModel struct {
 
 type CarModelWithVariants struct {
 	CarModel
-	Variants []CarVariantSummary `json:"variants"`
+	Variants []CarVariantSummaryWithMedia `json:"variants"`
 }
 
 type CarVariantSummary struct {
@@ -37,167 +38,224 @@ type CarVariantSummary struct {
 	Name      string   `json:"name"`
 	PriceBaht *float64 `json:"price_baht"`
 	Status    string   `json:"status"`
+	// Joined fields
+	BrandName *string `json:"brand_name,omitempty"`
+	ModelName *string `json:"model_name,omitempty"`
+	// Set only when the caller is signed in
+	Favorited *bool `json:"favorited,omitempty"`
+}
+
+// CarVariantSummaryWithMedia - a variant of the model detail endpoint; images
+// and colors are always lists, empty when the variant has none
+type CarVariantSummaryWithMedia struct {
+	CarVariantSummary
+	Images []CarImage `json:"images"`
+	Colors []CarColor `json:"colors"`
+}
+
+// CarImage - one picture of a variant's gallery, ordered by type then SortOrder
+type CarImage struct {
+	ID        int    `json:"id"`
+	VariantID int    `json:"variant_id"`
+	ImageURL  string `json:"image_url"`
+	ImageType string `json:"image_type"`
+	SortOrder int    `json:"sort_order"`
+	// Set for uploaded images
+	Width      *int              `json:"width,omitempty"`
+	Height     *int              `json:"height,omitempty"`
+	Blurhash   *string           `json:"blurhash,omitempty"`
+	Thumbnails map[string]string `json:"thumbnails,omitempty"`
+}
+
+// ImageTypes - car_images.image_type, in gallery order
+var ImageTypes = []string{"exterior", "interior", "detail", "color"}
+
+// CarColor - a paint option; EffectivePriceBaht is the variant price plus ExtraCost
+type CarColor struct {
+	ID                 int      `json:"id"`
+	VariantID          int      `json:"variant_id"`
+	Name               string   `json:"name"`
+	HexCode            *string  `json:"hex_code"`
+	ExtraCost          float64  `json:"extra_cost"`
+	EffectivePriceBaht *float64 `json:"effective_price_baht"`
 }
 
+// CarVariant - full spec of a variant. The db tag names the column each field is
+// selected from: car_variants by default, or alias.column for joined tables
+// (m = car_models, b = car_brands). Fields without a db tag are not columns.
 type CarVariant struct {
-	ID        int      `json:"id"`
-	ModelID   int      `json:"model_id"`
-	Name      string   `json:"name"`
-	PriceBaht *float64 `json:"price_baht"`
-	Status    string   `json:"status"`
+	ID        int      `json:"id" db:"id"`
+	ModelID   int      `json:"model_id" db:"model_id"`
+	Name      string   `json:"name" db:"name"`
+	PriceBaht *float64 `json:"price_baht" db:"price_baht"`
+	Status    string   `json:"status" db:"status"`
 
 	// Joined fields
-	BrandName      *string `json:"brand_name,omitempty"`
-	ModelName      *string `json:"model_name,omitempty"`
-	PowertrainType *string `json:"powertrain_type,omitempty"`
-	BodyType       *string `json:"body_type,omitempty"`
+	BrandName      *string `json:"brand_name,omitempty" db:"b.name"`
+	ModelName      *string `json:"model_name,omitempty" db:"m.name"`
+	PowertrainType *string `json:"powertrain_type,omitempty" db:"m.powertrain_type"`
+	BodyType       *string `json:"body_type,omitempty" db:"m.body_type"`
+	Segment        *string `json:"segment,omitempty" db:"m.segment"`
+
+	// Set only when the caller is signed in
+	Favorited *bool `json:"favorited,omitempty"`
+	// Latest price change, loaded from car_variant_prices on list and compare results
+	PriceChange *PriceChange `json:"price_change,omitempty"`
 
 	// Electric / Battery
-	BatteryCapacityKwh    *float64 `json:"battery_capacity_kwh"`
-	BatteryType           *string  `json:"battery_type"`
-	MotorPowerKw          *float64 `json:"motor_power_kw"`
-	MotorTorqueNm         *float64 `json:"motor_torque_nm"`
-	FrontMotorKw          *float64 `json:"front_motor_kw"`
-	RearMotorKw           *float64 `json:"rear_motor_kw"`
-	RangeKm               *int     `json:"range_km"`
-	RangeStandard         *string  `json:"range_standard"`
-	AcChargeKw            *float64 `json:"ac_charge_kw"`
-	DcChargeKw            *float64 `json:"dc_charge_kw"`
-	AcChargeTimeHrs       *float64 `json:"ac_charge_time_hrs"`
-	DcChargeTimeMins      *int     `json:"dc_charge_time_mins"`
-	ChargingPort          *string  `json:"charging_port"`
-	V2l                   *bool    `json:"v2l"`
-	V2g                   *bool    `json:"v2g"`
-	HeatPump              *bool    `json:"heat_pump"`
-	BatteryPreconditioning *bool   `json:"battery_preconditioning"`
+	BatteryCapacityKwh     *float64 `json:"battery_capacity_kwh" db:"battery_capacity_kwh"`
+	BatteryType            *string  `json:"battery_type" db:"battery_type"`
+	MotorPowerKw           *float64 `json:"motor_power_kw" db:"motor_power_kw"`
+	MotorTorqueNm          *float64 `json:"motor_torque_nm" db:"motor_torque_nm"`
+	FrontMotorKw           *float64 `json:"front_motor_kw" db:"front_motor_kw"`
+	RearMotorKw            *float64 `json:"rear_motor_kw" db:"rear_motor_kw"`
+	RangeKm                *int     `json:"range_km" db:"range_km"`
+	RangeStandard          *string  `json:"range_standard" db:"range_standard"`
+	AcChargeKw             *float64 `json:"ac_charge_kw" db:"ac_charge_kw"`
+	DcChargeKw             *float64 `json:"dc_charge_kw" db:"dc_charge_kw"`
+	AcChargeTimeHrs        *float64 `json:"ac_charge_time_hrs" db:"ac_charge_time_hrs"`
+	DcChargeTimeMins       *int     `json:"dc_charge_time_mins" db:"dc_charge_time_mins"`
+	ChargingPort           *string  `json:"charging_port" db:"charging_port"`
+	V2l                    *bool    `json:"v2l" db:"v2l"`
+	V2g                    *bool    `json:"v2g" db:"v2g"`
+	HeatPump               *bool    `json:"heat_pump" db:"heat_pump"`
+	BatteryPreconditioning *bool    `json:"battery_preconditioning" db:"battery_preconditioning"`
 
 	// Engine (ICE / Hybrid)
-	DisplacementCc     *int     `json:"displacement_cc"`
-	EngineType         *string  `json:"engine_type"`
-	Horsepower         *int     `json:"horsepower"`
-	EngineTorqueNm     *int     `json:"engine_torque_nm"`
-	FuelType           *string  `json:"fuel_type"`
-	FuelTankLiters     *float64 `json:"fuel_tank_liters"`
-	FuelConsumptionKml *float64 `json:"fuel_consumption_kml"`
-	Turbo              *bool    `json:"turbo"`
-	Transmission       *string  `json:"transmission"`
-	TransmissionSpeeds *int     `json:"transmission_speeds"`
+	DisplacementCc     *int     `json:"displacement_cc" db:"displacement_cc"`
+	EngineType         *string  `json:"engine_type" db:"engine_type"`
+	Horsepower         *int     `json:"horsepower" db:"horsepower"`
+	EngineTorqueNm     *int     `json:"engine_torque_nm" db:"engine_torque_nm"`
+	FuelType           *string  `json:"fuel_type" db:"fuel_type"`
+	FuelTankLiters     *float64 `json:"fuel_tank_liters" db:"fuel_tank_liters"`
+	FuelConsumptionKml *float64 `json:"fuel_consumption_kml" db:"fuel_consumption_kml"`
+	Turbo              *bool    `json:"turbo" db:"turbo"`
+	Transmission       *string  `json:"transmission" db:"transmission"`
+	TransmissionSpeeds *int     `json:"transmission_speeds" db:"transmission_speeds"`
 
 	// Combined (Hybrid)
-	SystemPowerHp  *int `json:"system_power_hp"`
-	SystemTorqueNm *int `json:"system_torque_nm"`
-	EvRangeKm      *int `json:"ev_range_km"`
+	SystemPowerHp  *int `json:"system_power_hp" db:"system_power_hp"`
+	SystemTorqueNm *int `json:"system_torque_nm" db:"system_torque_nm"`
+	EvRangeKm      *int `json:"ev_range_km" db:"ev_range_km"`
 
 	// Performance
-	TopSpeedKmh      *int     `json:"top_speed_kmh"`
-	Acceleration0100 *float64 `json:"acceleration_0_100"`
+	TopSpeedKmh      *int     `json:"top_speed_kmh" db:"top_speed_kmh"`
+	Acceleration0100 *float64 `json:"acceleration_0_100" db:"acceleration_0_100"`
 
 	// Dimensions
-	LengthMm            *int `json:"length_mm"`
-	WidthMm              *int `json:"width_mm"`
-	HeightMm             *int `json:"height_mm"`
-	WheelbaseMm          *int `json:"wheelbase_mm"`
-	GroundClearanceMm    *int `json:"ground_clearance_mm"`
-	CurbWeightKg         *int `json:"curb_weight_kg"`
-	GrossWeightKg        *int `json:"gross_weight_kg"`
-	TrunkCapacityLiters  *int `json:"trunk_capacity_liters"`
-	TrunkMaxLiters       *int `json:"trunk_max_liters"`
-	FrunkCapacityLiters  *int `json:"frunk_capacity_liters"`
+	LengthMm            *int `json:"length_mm" db:"length_mm"`
+	WidthMm             *int `json:"width_mm" db:"width_mm"`
+	HeightMm            *int `json:"height_mm" db:"height_mm"`
+	WheelbaseMm         *int `json:"wheelbase_mm" db:"wheelbase_mm"`
+	GroundClearanceMm   *int `json:"ground_clearance_mm" db:"ground_clearance_mm"`
+	CurbWeightKg        *int `json:"curb_weight_kg" db:"curb_weight_kg"`
+	GrossWeightKg       *int `json:"gross_weight_kg" db:"gross_weight_kg"`
+	TrunkCapacityLiters *int `json:"trunk_capacity_liters" db:"trunk_capacity_liters"`
+	TrunkMaxLiters      *int `json:"trunk_max_liters" db:"trunk_max_liters"`
+	FrunkCapacityLiters *int `json:"frunk_capacity_liters" db:"frunk_capacity_liters"`
 
 	// Drive
-	DriveType       *string `json:"drive_type"`
-	FrontSuspension *string `json:"front_suspension"`
-	RearSuspension  *string `json:"rear_suspension"`
-	FrontBrakes     *string `json:"front_brakes"`
-	RearBrakes      *string `json:"rear_brakes"`
-	TireSizeFront   *string `json:"tire_size_front"`
-	TireSizeRear    *string `json:"tire_size_rear"`
-	SpareTire       *string `json:"spare_tire"`
+	DriveType       *string `json:"drive_type" db:"drive_type"`
+	FrontSuspension *string `json:"front_suspension" db:"front_suspension"`
+	RearSuspension  *string `json:"rear_suspension" db:"rear_suspension"`
+	FrontBrakes     *string `json:"front_brakes" db:"front_brakes"`
+	RearBrakes      *string `json:"rear_brakes" db:"rear_brakes"`
+	TireSizeFront   *string `json:"tire_size_front" db:"tire_size_front"`
+	TireSizeRear    *string `json:"tire_size_rear" db:"tire_size_rear"`
+	SpareTire       *string `json:"spare_tire" db:"spare_tire"`
 
 	// Safety
-	Airbags            *int  `json:"airbags"`
-	Abs                *bool `json:"abs"`
-	Esc                *bool `json:"esc"`
-	TractionControl    *bool `json:"traction_control"`
-	HillStartAssist    *bool `json:"hill_start_assist"`
-	HillDescentControl *bool `json:"hill_descent_control"`
-	Tpms               *bool `json:"tpms"`
-	Isofix             *bool `json:"isofix"`
-	ParkingSensorFront *bool `json:"parking_sensor_front"`
-	ParkingSensorRear  *bool `json:"parking_sensor_rear"`
-	CameraRear         *bool `json:"camera_rear"`
-	Camera360          *bool `json:"camera_360"`
-	AutoParking        *bool `json:"auto_parking"`
+	Airbags            *int  `json:"airbags" db:"airbags"`
+	Abs                *bool `json:"abs" db:"abs"`
+	Esc                *bool `json:"esc" db:"esc"`
+	TractionControl    *bool `json:"traction_control" db:"traction_control"`
+	HillStartAssist    *bool `json:"hill_start_assist" db:"hill_start_assist"`
+	HillDescentControl *bool `json:"hill_descent_control" db:"hill_descent_control"`
+	Tpms               *bool `json:"tpms" db:"tpms"`
+	Isofix             *bool `json:"isofix" db:"isofix"`
+	ParkingSensorFront *bool `json:"parking_sensor_front" db:"parking_sensor_front"`
+	ParkingSensorRear  *bool `json:"parking_sensor_rear" db:"parking_sensor_rear"`
+	CameraRear         *bool `json:"camera_rear" db:"camera_rear"`
+	Camera360          *bool `json:"camera_360" db:"camera_360"`
+	AutoParking        *bool `json:"auto_parking" db:"auto_parking"`
 
 	// ADAS
-	Aeb                      *bool   `json:"aeb"`
-	Fcw                      *bool   `json:"fcw"`
-	Lka                      *bool   `json:"lka"`
-	Ldw                      *bool   `json:"ldw"`
-	Bsd                      *bool   `json:"bsd"`
-	Rcta                     *bool   `json:"rcta"`
-	Acc                      *bool   `json:"acc"`
-	AccStopGo                *bool   `json:"acc_stop_go"`
-	DriverMonitoring         *string `json:"driver_monitoring"`
-	TrafficSignRecognition   *bool   `json:"traffic_sign_recognition"`
-	NightVision              *bool   `json:"night_vision"`
-	AdasLevel                *string `json:"adas_level"`
+	Aeb                    *bool   `json:"aeb" db:"aeb"`
+	Fcw                    *bool   `json:"fcw" db:"fcw"`
+	Lka                    *bool   `json:"lka" db:"lka"`
+	Ldw                    *bool   `json:"ldw" db:"ldw"`
+	Bsd                    *bool   `json:"bsd" db:"bsd"`
+	Rcta                   *bool   `json:"rcta" db:"rcta"`
+	Acc                    *bool   `json:"acc" db:"acc"`
+	AccStopGo              *bool   `json:"acc_stop_go" db:"acc_stop_go"`
+	DriverMonitoring       *string `json:"driver_monitoring" db:"driver_monitoring"`
+	TrafficSignRecognition *bool   `json:"traffic_sign_recognition" db:"traffic_sign_recognition"`
+	NightVision            *bool   `json:"night_vision" db:"night_vision"`
+	AdasLevel              *string `json:"adas_level" db:"adas_level"`
 
 	// NCAP
-	NcapRating *float64 `json:"ncap_rating"`
-	NcapBody   *string  `json:"ncap_body"`
-	NcapYear   *int     `json:"ncap_year"`
+	NcapRating *float64 `json:"ncap_rating" db:"ncap_rating"`
+	NcapBody   *string  `json:"ncap_body" db:"ncap_body"`
+	NcapYear   *int     `json:"ncap_year" db:"ncap_year"`
 
 	// Comfort
-	Seats                *int    `json:"seats"`
-	SeatMaterial         *string `json:"seat_material"`
-	DriverSeatElectric   *bool   `json:"driver_seat_electric"`
-	PassengerSeatElectric *bool  `json:"passenger_seat_electric"`
-	DriverSeatMemory     *bool   `json:"driver_seat_memory"`
-	VentilatedSeatsFront *bool   `json:"ventilated_seats_front"`
-	VentilatedSeatsRear  *bool   `json:"ventilated_seats_rear"`
-	HeatedSeatsFront     *bool   `json:"heated_seats_front"`
-	HeatedSeatsRear      *bool   `json:"heated_seats_rear"`
-	RearSeatRecline      *bool   `json:"rear_seat_recline"`
-	AcZones              *int    `json:"ac_zones"`
-	RearAcVents          *bool   `json:"rear_ac_vents"`
+	Seats                 *int    `json:"seats" db:"seats"`
+	SeatMaterial          *string `json:"seat_material" db:"seat_material"`
+	DriverSeatElectric    *bool   `json:"driver_seat_electric" db:"driver_seat_electric"`
+	PassengerSeatElectric *bool   `json:"passenger_seat_electric" db:"passenger_seat_electric"`
+	DriverSeatMemory      *bool   `json:"driver_seat_memory" db:"driver_seat_memory"`
+	VentilatedSeatsFront  *bool   `json:"ventilated_seats_front" db:"ventilated_seats_front"`
+	VentilatedSeatsRear   *bool   `json:"ventilated_seats_rear" db:"ventilated_seats_rear"`
+	HeatedSeatsFront      *bool   `json:"heated_seats_front" db:"heated_seats_front"`
+	HeatedSeatsRear       *bool   `json:"heated_seats_rear" db:"heated_seats_rear"`
+	RearSeatRecline       *bool   `json:"rear_seat_recline" db:"rear_seat_recline"`
+	AcZones               *int    `json:"ac_zones" db:"ac_zones"`
+	RearAcVents           *bool   `json:"rear_ac_vents" db:"rear_ac_vents"`
 
 	// Infotainment
-	ScreenSizeInch       *float64 `json:"screen_size_inch"`
-	ScreenType           *string  `json:"screen_type"`
-	DigitalCluster       *bool    `json:"digital_cluster"`
-	ClusterSizeInch      *float64 `json:"cluster_size_inch"`
-	Hud                  *bool    `json:"hud"`
-	SpeakerBrand         *string  `json:"speaker_brand"`
-	SpeakerCount         *int     `json:"speaker_count"`
-	AppleCarplay         *bool    `json:"apple_carplay"`
-	AndroidAuto          *bool    `json:"android_auto"`
-	WirelessCarplay      *bool    `json:"wireless_carplay"`
-	WirelessAndroidAuto  *bool    `json:"wireless_android_auto"`
-	WirelessPhoneCharging *bool   `json:"wireless_phone_charging"`
-	UsbCPorts            *int     `json:"usb_c_ports"`
-	UsbAPorts            *int     `json:"usb_a_ports"`
-	Bluetooth            *string  `json:"bluetooth"`
-	OtaUpdate            *bool    `json:"ota_update"`
+	ScreenSizeInch        *float64 `json:"screen_size_inch" db:"screen_size_inch"`
+	ScreenType            *string  `json:"screen_type" db:"screen_type"`
+	DigitalCluster        *bool    `json:"digital_cluster" db:"digital_cluster"`
+	ClusterSizeInch       *float64 `json:"cluster_size_inch" db:"cluster_size_inch"`
+	Hud                   *bool    `json:"hud" db:"hud"`
+	SpeakerBrand          *string  `json:"speaker_brand" db:"speaker_brand"`
+	SpeakerCount          *int     `json:"speaker_count" db:"speaker_count"`
+	AppleCarplay          *bool    `json:"apple_carplay" db:"apple_carplay"`
+	AndroidAuto           *bool    `json:"android_auto" db:"android_auto"`
+	WirelessCarplay       *bool    `json:"wireless_carplay" db:"wireless_carplay"`
+	WirelessAndroidAuto   *bool    `json:"wireless_android_auto" db:"wireless_android_auto"`
+	WirelessPhoneCharging *bool    `json:"wireless_phone_charging" db:"wireless_phone_charging"`
+	UsbCPorts             *int     `json:"usb_c_ports" db:"usb_c_ports"`
+	UsbAPorts             *int     `json:"usb_a_ports" db:"usb_a_ports"`
+	Bluetooth             *string  `json:"bluetooth" db:"bluetooth"`
+	OtaUpdate             *bool    `json:"ota_update" db:"ota_update"`
 
 	// Exterior
-	HeadlightType      *string `json:"headlight_type"`
-	Drl                *bool   `json:"drl"`
-	AutoHeadlights     *bool   `json:"auto_headlights"`
-	AdaptiveHeadlights *bool   `json:"adaptive_headlights"`
-	FogLights          *bool   `json:"fog_lights"`
-	Sunroof            *string `json:"sunroof"`
-	PowerTailgate      *bool   `json:"power_tailgate"`
-	HandsFreeTailgate  *bool   `json:"hands_free_tailgate"`
-	KeylessEntry       *bool   `json:"keyless_entry"`
-	PushStart          *bool   `json:"push_start"`
-	AutoFoldingMirrors *bool   `json:"auto_folding_mirrors"`
-	RainSensingWipers  *bool   `json:"rain_sensing_wipers"`
-	RoofRails          *bool   `json:"roof_rails"`
+	HeadlightType      *string `json:"headlight_type" db:"headlight_type"`
+	Drl                *bool   `json:"drl" db:"drl"`
+	AutoHeadlights     *bool   `json:"auto_headlights" db:"auto_headlights"`
+	AdaptiveHeadlights *bool   `json:"adaptive_headlights" db:"adaptive_headlights"`
+	FogLights          *bool   `json:"fog_lights" db:"fog_lights"`
+	Sunroof            *string `json:"sunroof" db:"sunroof"`
+	PowerTailgate      *bool   `json:"power_tailgate" db:"power_tailgate"`
+	HandsFreeTailgate  *bool   `json:"hands_free_tailgate" db:"hands_free_tailgate"`
+	KeylessEntry       *bool   `json:"keyless_entry" db:"keyless_entry"`
+	PushStart          *bool   `json:"push_start" db:"push_start"`
+	AutoFoldingMirrors *bool   `json:"auto_folding_mirrors" db:"auto_folding_mirrors"`
+	RainSensingWipers  *bool   `json:"rain_sensing_wipers" db:"rain_sensing_wipers"`
+	RoofRails          *bool   `json:"roof_rails" db:"roof_rails"`
 
 	// Warranty
-	WarrantyYears       *int `json:"warranty_years"`
-	WarrantyKm          *int `json:"warranty_km"`
-	BatteryWarrantyYears *int `json:"battery_warranty_years"`
-	BatteryWarrantyKm   *int `json:"battery_warranty_km"`
+	WarrantyYears        *int `json:"warranty_years" db:"warranty_years"`
+	WarrantyKm           *int `json:"warranty_km" db:"warranty_km"`
+	BatteryWarrantyYears *int `json:"battery_warranty_years" db:"battery_warranty_years"`
+	BatteryWarrantyKm    *int `json:"battery_warranty_km" db:"battery_warranty_km"`
+}
+
+// CarVariantWithMedia - the variant detail response, with the gallery and
+// colour options always present as lists
+type CarVariantWithMedia struct {
+	CarVariant
+	Images []CarImage `json:"images"`
+	Colors []CarColor `json:"colors"`
 }
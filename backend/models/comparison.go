@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+type UserComparison struct {
+	ID         int     `json:"id"`
+	UserID     int     `json:"user_id"`
+	VariantIDs []int   `json:"variant_ids"`
+	Title      *string `json:"title"`
+	SortOrder  int     `json:"sort_order"`
+	// Public sharing (nil slug = not shared)
+	ShareSlug      *string    `json:"share_slug"`
+	ShareExpiresAt *time.Time `json:"share_expires_at"`
+	ViewCount      int        `json:"view_count"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
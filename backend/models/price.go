@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// PricePoint - one row of car_variant_prices. PreviousPriceBaht is nil for
+// the first recorded price of a variant.
+type PricePoint struct {
+	PriceBaht         *float64  `json:"price_baht"`
+	PreviousPriceBaht *float64  `json:"previous_price_baht"`
+	ChangedAt         time.Time `json:"changed_at"`
+}
+
+// PriceChange - the latest change of a variant's price
+type PriceChange struct {
+	ChangedAt         time.Time `json:"changed_at"`
+	PreviousPriceBaht *float64  `json:"previous_price_baht"`
+	PriceBaht         *float64  `json:"price_baht"`
+	Delta             *float64  `json:"delta"`
+	Percent           *float64  `json:"percent"`
+}
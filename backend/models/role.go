@@ -0,0 +1,9 @@
+package models
+
+// Role - a named set of permissions
+type Role struct {
+	ID          int      `json:"id"`
+	Name        string   `json:"name"`
+	Description *string  `json:"description"`
+	Permissions []string `json:"permissions"`
+}
@@ -0,0 +1,259 @@
+package models
+
+// Value types of a spec field
+const (
+	SpecTypeInteger = "integer"
+	SpecTypeDecimal = "decimal"
+	SpecTypeBoolean = "boolean"
+	SpecTypeText    = "text"
+	SpecTypeEnum    = "enum"
+)
+
+// Comparison direction of a spec field: which value wins when comparing variants.
+// For booleans "higher" means having the feature is better.
+const (
+	CompareHigher = "higher"
+	CompareLower  = "lower"
+	CompareNone   = "none"
+)
+
+// SpecSection - a group of spec fields, in display order
+type SpecSection struct {
+	Key     string `json:"key"`
+	LabelTh string `json:"label_th"`
+	LabelEn string `json:"label_en"`
+}
+
+// SpecField - metadata for one column of the full variant spec
+type SpecField struct {
+	Key     string   `json:"key"`
+	Section string   `json:"section"`
+	Type    string   `json:"type"`
+	Unit    string   `json:"unit,omitempty"`
+	LabelTh string   `json:"label_th"`
+	LabelEn string   `json:"label_en"`
+	Compare string   `json:"compare"`
+	Options []string `json:"options,omitempty"`
+}
+
+// Allowed values of the ENUM columns in migrations/car_tables.sql
+var (
+	PowertrainTypes = []string{"BEV", "PHEV", "HEV", "MHEV", "ICE"}
+	BodyTypes       = []string{"sedan", "suv", "crossover", "hatchback", "mpv", "pickup", "coupe", "wagon", "van"}
+	Segments        = []string{"a", "b", "c", "d", "e", "s"}
+	CarStatuses     = []string{"on_sale", "coming_soon", "discontinued"}
+	FuelTypes       = []string{"gasoline_95", "gasoline_91", "diesel", "e20", "e85", "lpg"}
+	DriveTypes      = []string{"FWD", "RWD", "AWD", "4WD"}
+	SunroofTypes    = []string{"none", "standard", "panoramic", "glass_roof"}
+	BrandStatuses   = []string{"active", "discontinued"}
+)
+
+var SpecSections = []SpecSection{
+	{Key: "identity", LabelTh: "ข้อมูลรุ่น", LabelEn: "Identity"},
+	{Key: "overview", LabelTh: "ภาพรวม", LabelEn: "Overview"},
+	{Key: "battery", LabelTh: "แบตเตอรี่และมอเตอร์", LabelEn: "Electric / Battery"},
+	{Key: "engine", LabelTh: "เครื่องยนต์", LabelEn: "Engine"},
+	{Key: "hybrid", LabelTh: "ระบบไฮบริด", LabelEn: "Combined (Hybrid)"},
+	{Key: "performance", LabelTh: "สมรรถนะ", LabelEn: "Performance"},
+	{Key: "dimensions", LabelTh: "ขนาดและน้ำหนัก", LabelEn: "Dimensions"},
+	{Key: "drive", LabelTh: "ระบบขับเคลื่อนและช่วงล่าง", LabelEn: "Drive"},
+	{Key: "safety", LabelTh: "ความปลอดภัย", LabelEn: "Safety"},
+	{Key: "adas", LabelTh: "ระบบช่วยขับขี่", LabelEn: "ADAS"},
+	{Key: "ncap", LabelTh: "ผลทดสอบการชน", LabelEn: "NCAP"},
+	{Key: "comfort", LabelTh: "ความสะดวกสบาย", LabelEn: "Comfort"},
+	{Key: "infotainment", LabelTh: "ระบบความบันเทิง", LabelEn: "Infotainment"},
+	{Key: "exterior", LabelTh: "ภายนอก", LabelEn: "Exterior"},
+	{Key: "warranty", LabelTh: "การรับประกัน", LabelEn: "Warranty"},
+}
+
+// SpecFields - every column of the full variant spec, in display order.
+// Keys are the json keys of models.CarVariant.
+var SpecFields = concatSections(
+	inSection("identity",
+		SpecField{Key: "id", Type: SpecTypeInteger, LabelTh: "รหัสรุ่นย่อย", LabelEn: "Variant ID", Compare: CompareNone},
+		SpecField{Key: "model_id", Type: SpecTypeInteger, LabelTh: "รหัสรุ่น", LabelEn: "Model ID", Compare: CompareNone},
+		SpecField{Key: "name", Type: SpecTypeText, LabelTh: "ชื่อรุ่นย่อย", LabelEn: "Variant", Compare: CompareNone},
+		SpecField{Key: "brand_name", Type: SpecTypeText, LabelTh: "ยี่ห้อ", LabelEn: "Brand", Compare: CompareNone},
+		SpecField{Key: "model_name", Type: SpecTypeText, LabelTh: "รุ่น", LabelEn: "Model", Compare: CompareNone},
+	),
+	inSection("overview",
+		SpecField{Key: "price_baht", Type: SpecTypeDecimal, Unit: "THB", LabelTh: "ราคา", LabelEn: "Price", Compare: CompareLower},
+		SpecField{Key: "status", Type: SpecTypeEnum, LabelTh: "สถานะ", LabelEn: "Status", Compare: CompareNone, Options: CarStatuses},
+		SpecField{Key: "powertrain_type", Type: SpecTypeEnum, LabelTh: "ประเภทขุมพลัง", LabelEn: "Powertrain", Compare: CompareNone, Options: PowertrainTypes},
+		SpecField{Key: "body_type", Type: SpecTypeEnum, LabelTh: "ประเภทตัวถัง", LabelEn: "Body type", Compare: CompareNone, Options: BodyTypes},
+		SpecField{Key: "segment", Type: SpecTypeEnum, LabelTh: "เซกเมนต์", LabelEn: "Segment", Compare: CompareNone, Options: Segments},
+	),
+	inSection("battery",
+		SpecField{Key: "battery_capacity_kwh", Type: SpecTypeDecimal, Unit: "kWh", LabelTh: "ความจุแบตเตอรี่", LabelEn: "Battery capacity", Compare: CompareHigher},
+		SpecField{Key: "battery_type", Type: SpecTypeText, LabelTh: "ชนิดแบตเตอรี่", LabelEn: "Battery type", Compare: CompareNone},
+		SpecField{Key: "motor_power_kw", Type: SpecTypeDecimal, Unit: "kW", LabelTh: "กำลังมอเตอร์", LabelEn: "Motor power", Compare: CompareHigher},
+		SpecField{Key: "motor_torque_nm", Type: SpecTypeDecimal, Unit: "Nm", LabelTh: "แรงบิดมอเตอร์", LabelEn: "Motor torque", Compare: CompareHigher},
+		SpecField{Key: "front_motor_kw", Type: SpecTypeDecimal, Unit: "kW", LabelTh: "กำลังมอเตอร์หน้า", LabelEn: "Front motor power", Compare: CompareHigher},
+		SpecField{Key: "rear_motor_kw", Type: SpecTypeDecimal, Unit: "kW", LabelTh: "กำลังมอเตอร์หลัง", LabelEn: "Rear motor power", Compare: CompareHigher},
+		SpecField{Key: "range_km", Type: SpecTypeInteger, Unit: "km", LabelTh: "ระยะทางต่อการชาร์จ", LabelEn: "Range", Compare: CompareHigher},
+		SpecField{Key: "range_standard", Type: SpecTypeText, LabelTh: "มาตรฐานการวัดระยะทาง", LabelEn: "Range standard", Compare: CompareNone},
+		SpecField{Key: "ac_charge_kw", Type: SpecTypeDecimal, Unit: "kW", LabelTh: "กำลังชาร์จ AC", LabelEn: "AC charging power", Compare: CompareHigher},
+		SpecField{Key: "dc_charge_kw", Type: SpecTypeDecimal, Unit: "kW", LabelTh: "กำลังชาร์จ DC", LabelEn: "DC charging power", Compare: CompareHigher},
+		SpecField{Key: "ac_charge_time_hrs", Type: SpecTypeDecimal, Unit: "h", LabelTh: "เวลาชาร์จ AC", LabelEn: "AC charging time", Compare: CompareLower},
+		SpecField{Key: "dc_charge_time_mins", Type: SpecTypeInteger, Unit: "min", LabelTh: "เวลาชาร์จ DC", LabelEn: "DC charging time", Compare: CompareLower},
+		SpecField{Key: "charging_port", Type: SpecTypeText, LabelTh: "หัวชาร์จ", LabelEn: "Charging port", Compare: CompareNone},
+		SpecField{Key: "v2l", Type: SpecTypeBoolean, LabelTh: "จ่ายไฟภายนอก (V2L)", LabelEn: "Vehicle-to-load (V2L)", Compare: CompareHigher},
+		SpecField{Key: "v2g", Type: SpecTypeBoolean, LabelTh: "จ่ายไฟเข้ากริด (V2G)", LabelEn: "Vehicle-to-grid (V2G)", Compare: CompareHigher},
+		SpecField{Key: "heat_pump", Type: SpecTypeBoolean, LabelTh: "ฮีตปั๊ม", LabelEn: "Heat pump", Compare: CompareHigher},
+		SpecField{Key: "battery_preconditioning", Type: SpecTypeBoolean, LabelTh: "ปรับอุณหภูมิแบตเตอรี่ก่อนชาร์จ", LabelEn: "Battery preconditioning", Compare: CompareHigher},
+	),
+	inSection("engine",
+		SpecField{Key: "displacement_cc", Type: SpecTypeInteger, Unit: "cc", LabelTh: "ความจุกระบอกสูบ", LabelEn: "Displacement", Compare: CompareNone},
+		SpecField{Key: "engine_type", Type: SpecTypeText, LabelTh: "แบบเครื่องยนต์", LabelEn: "Engine type", Compare: CompareNone},
+		SpecField{Key: "horsepower", Type: SpecTypeInteger, Unit: "hp", LabelTh: "แรงม้า", LabelEn: "Horsepower", Compare: CompareHigher},
+		SpecField{Key: "engine_torque_nm", Type: SpecTypeInteger, Unit: "Nm", LabelTh: "แรงบิดเครื่องยนต์", LabelEn: "Engine torque", Compare: CompareHigher},
+		SpecField{Key: "fuel_type", Type: SpecTypeEnum, LabelTh: "ชนิดเชื้อเพลิง", LabelEn: "Fuel type", Compare: CompareNone, Options: FuelTypes},
+		SpecField{Key: "fuel_tank_liters", Type: SpecTypeDecimal, Unit: "L", LabelTh: "ความจุถังน้ำมัน", LabelEn: "Fuel tank", Compare: CompareHigher},
+		SpecField{Key: "fuel_consumption_kml", Type: SpecTypeDecimal, Unit: "km/L", LabelTh: "อัตราสิ้นเปลือง", LabelEn: "Fuel economy", Compare: CompareHigher},
+		SpecField{Key: "turbo", Type: SpecTypeBoolean, LabelTh: "เทอร์โบ", LabelEn: "Turbo", Compare: CompareNone},
+		SpecField{Key: "transmission", Type: SpecTypeText, LabelTh: "ระบบเกียร์", LabelEn: "Transmission", Compare: CompareNone},
+		SpecField{Key: "transmission_speeds", Type: SpecTypeInteger, Unit: "speeds", LabelTh: "จำนวนเกียร์", LabelEn: "Gears", Compare: CompareNone},
+	),
+	inSection("hybrid",
+		SpecField{Key: "system_power_hp", Type: SpecTypeInteger, Unit: "hp", LabelTh: "กำลังรวมของระบบ", LabelEn: "System power", Compare: CompareHigher},
+		SpecField{Key: "system_torque_nm", Type: SpecTypeInteger, Unit: "Nm", LabelTh: "แรงบิดรวมของระบบ", LabelEn: "System torque", Compare: CompareHigher},
+		SpecField{Key: "ev_range_km", Type: SpecTypeInteger, Unit: "km", LabelTh: "ระยะทางโหมดไฟฟ้า", LabelEn: "EV-only range", Compare: CompareHigher},
+	),
+	inSection("performance",
+		SpecField{Key: "top_speed_kmh", Type: SpecTypeInteger, Unit: "km/h", LabelTh: "ความเร็วสูงสุด", LabelEn: "Top speed", Compare: CompareHigher},
+		SpecField{Key: "acceleration_0_100", Type: SpecTypeDecimal, Unit: "s", LabelTh: "อัตราเร่ง 0-100 กม./ชม.", LabelEn: "0-100 km/h", Compare: CompareLower},
+	),
+	inSection("dimensions",
+		SpecField{Key: "length_mm", Type: SpecTypeInteger, Unit: "mm", LabelTh: "ความยาว", LabelEn: "Length", Compare: CompareNone},
+		SpecField{Key: "width_mm", Type: SpecTypeInteger, Unit: "mm", LabelTh: "ความกว้าง", LabelEn: "Width", Compare: CompareNone},
+		SpecField{Key: "height_mm", Type: SpecTypeInteger, Unit: "mm", LabelTh: "ความสูง", LabelEn: "Height", Compare: CompareNone},
+		SpecField{Key: "wheelbase_mm", Type: SpecTypeInteger, Unit: "mm", LabelTh: "ระยะฐานล้อ", LabelEn: "Wheelbase", Compare: CompareHigher},
+		SpecField{Key: "ground_clearance_mm", Type: SpecTypeInteger, Unit: "mm", LabelTh: "ระยะต่ำสุดจากพื้น", LabelEn: "Ground clearance", Compare: CompareHigher},
+		SpecField{Key: "curb_weight_kg", Type: SpecTypeInteger, Unit: "kg", LabelTh: "น้ำหนักรถเปล่า", LabelEn: "Curb weight", Compare: CompareLower},
+		SpecField{Key: "gross_weight_kg", Type: SpecTypeInteger, Unit: "kg", LabelTh: "น้ำหนักรวม", LabelEn: "Gross weight", Compare: CompareNone},
+		SpecField{Key: "trunk_capacity_liters", Type: SpecTypeInteger, Unit: "L", LabelTh: "ความจุห้องเก็บสัมภาระ", LabelEn: "Trunk capacity", Compare: CompareHigher},
+		SpecField{Key: "trunk_max_liters", Type: SpecTypeInteger, Unit: "L", LabelTh: "ความจุสัมภาระสูงสุด (พับเบาะ)", LabelEn: "Trunk capacity (seats folded)", Compare: CompareHigher},
+		SpecField{Key: "frunk_capacity_liters", Type: SpecTypeInteger, Unit: "L", LabelTh: "ความจุช่องเก็บของด้านหน้า", LabelEn: "Frunk capacity", Compare: CompareHigher},
+	),
+	inSection("drive",
+		SpecField{Key: "drive_type", Type: SpecTypeEnum, LabelTh: "ระบบขับเคลื่อน", LabelEn: "Drive type", Compare: CompareNone, Options: DriveTypes},
+		SpecField{Key: "front_suspension", Type: SpecTypeText, LabelTh: "ช่วงล่างหน้า", LabelEn: "Front suspension", Compare: CompareNone},
+		SpecField{Key: "rear_suspension", Type: SpecTypeText, LabelTh: "ช่วงล่างหลัง", LabelEn: "Rear suspension", Compare: CompareNone},
+		SpecField{Key: "front_brakes", Type: SpecTypeText, LabelTh: "เบรกหน้า", LabelEn: "Front brakes", Compare: CompareNone},
+		SpecField{Key: "rear_brakes", Type: SpecTypeText, LabelTh: "เบรกหลัง", LabelEn: "Rear brakes", Compare: CompareNone},
+		SpecField{Key: "tire_size_front", Type: SpecTypeText, LabelTh: "ขนาดยางหน้า", LabelEn: "Front tires", Compare: CompareNone},
+		SpecField{Key: "tire_size_rear", Type: SpecTypeText, LabelTh: "ขนาดยางหลัง", LabelEn: "Rear tires", Compare: CompareNone},
+		SpecField{Key: "spare_tire", Type: SpecTypeText, LabelTh: "ยางอะไหล่", LabelEn: "Spare tire", Compare: CompareNone},
+	),
+	inSection("safety",
+		SpecField{Key: "airbags", Type: SpecTypeInteger, Unit: "airbags", LabelTh: "จำนวนถุงลมนิรภัย", LabelEn: "Airbags", Compare: CompareHigher},
+		SpecField{Key: "abs", Type: SpecTypeBoolean, LabelTh: "ระบบเบรก ABS", LabelEn: "ABS", Compare: CompareHigher},
+		SpecField{Key: "esc", Type: SpecTypeBoolean, LabelTh: "ระบบควบคุมการทรงตัว", LabelEn: "Electronic stability control", Compare: CompareHigher},
+		SpecField{Key: "traction_control", Type: SpecTypeBoolean, LabelTh: "ระบบป้องกันล้อหมุนฟรี", LabelEn: "Traction control", Compare: CompareHigher},
+		SpecField{Key: "hill_start_assist", Type: SpecTypeBoolean, LabelTh: "ระบบช่วยออกตัวบนทางลาดชัน", LabelEn: "Hill start assist", Compare: CompareHigher},
+		SpecField{Key: "hill_descent_control", Type: SpecTypeBoolean, LabelTh: "ระบบช่วยควบคุมความเร็วขณะลงเขา", LabelEn: "Hill descent control", Compare: CompareHigher},
+		SpecField{Key: "tpms", Type: SpecTypeBoolean, LabelTh: "ระบบแจ้งเตือนแรงดันลมยาง", LabelEn: "Tire pressure monitoring", Compare: CompareHigher},
+		SpecField{Key: "isofix", Type: SpecTypeBoolean, LabelTh: "จุดยึดเบาะเด็ก ISOFIX", LabelEn: "ISOFIX", Compare: CompareHigher},
+		SpecField{Key: "parking_sensor_front", Type: SpecTypeBoolean, LabelTh: "เซ็นเซอร์กะระยะด้านหน้า", LabelEn: "Front parking sensors", Compare: CompareHigher},
+		SpecField{Key: "parking_sensor_rear", Type: SpecTypeBoolean, LabelTh: "เซ็นเซอร์กะระยะด้านหลัง", LabelEn: "Rear parking sensors", Compare: CompareHigher},
+		SpecField{Key: "camera_rear", Type: SpecTypeBoolean, LabelTh: "กล้องมองหลัง", LabelEn: "Rear camera", Compare: CompareHigher},
+		SpecField{Key: "camera_360", Type: SpecTypeBoolean, LabelTh: "กล้องรอบคัน 360 องศา", LabelEn: "360° camera", Compare: CompareHigher},
+		SpecField{Key: "auto_parking", Type: SpecTypeBoolean, LabelTh: "ระบบช่วยจอดอัตโนมัติ", LabelEn: "Automatic parking", Compare: CompareHigher},
+	),
+	inSection("adas",
+		SpecField{Key: "aeb", Type: SpecTypeBoolean, LabelTh: "ระบบเบรกฉุกเฉินอัตโนมัติ", LabelEn: "Autonomous emergency braking", Compare: CompareHigher},
+		SpecField{Key: "fcw", Type: SpecTypeBoolean, LabelTh: "ระบบเตือนการชนด้านหน้า", LabelEn: "Forward collision warning", Compare: CompareHigher},
+		SpecField{Key: "lka", Type: SpecTypeBoolean, LabelTh: "ระบบช่วยควบคุมรถให้อยู่ในเลน", LabelEn: "Lane keeping assist", Compare: CompareHigher},
+		SpecField{Key: "ldw", Type: SpecTypeBoolean, LabelTh: "ระบบเตือนออกนอกเลน", LabelEn: "Lane departure warning", Compare: CompareHigher},
+		SpecField{Key: "bsd", Type: SpecTypeBoolean, LabelTh: "ระบบตรวจจับจุดอับสายตา", LabelEn: "Blind spot detection", Compare: CompareHigher},
+		SpecField{Key: "rcta", Type: SpecTypeBoolean, LabelTh: "ระบบเตือนรถตัดผ่านขณะถอย", LabelEn: "Rear cross traffic alert", Compare: CompareHigher},
+		SpecField{Key: "acc", Type: SpecTypeBoolean, LabelTh: "ระบบควบคุมความเร็วอัตโนมัติแปรผัน", LabelEn: "Adaptive cruise control", Compare: CompareHigher},
+		SpecField{Key: "acc_stop_go", Type: SpecTypeBoolean, LabelTh: "ครูสคอนโทรลแบบ Stop & Go", LabelEn: "ACC stop & go", Compare: CompareHigher},
+		SpecField{Key: "driver_monitoring", Type: SpecTypeText, LabelTh: "ระบบตรวจจับความพร้อมผู้ขับ", LabelEn: "Driver monitoring", Compare: CompareNone},
+		SpecField{Key: "traffic_sign_recognition", Type: SpecTypeBoolean, LabelTh: "ระบบอ่านป้ายจราจร", LabelEn: "Traffic sign recognition", Compare: CompareHigher},
+		SpecField{Key: "night_vision", Type: SpecTypeBoolean, LabelTh: "ระบบมองเห็นกลางคืน", LabelEn: "Night vision", Compare: CompareHigher},
+		SpecField{Key: "adas_level", Type: SpecTypeText, LabelTh: "ระดับระบบช่วยขับ", LabelEn: "ADAS level", Compare: CompareNone},
+	),
+	inSection("ncap",
+		SpecField{Key: "ncap_rating", Type: SpecTypeDecimal, Unit: "stars", LabelTh: "คะแนนความปลอดภัย NCAP", LabelEn: "NCAP rating", Compare: CompareHigher},
+		SpecField{Key: "ncap_body", Type: SpecTypeText, LabelTh: "หน่วยงานทดสอบ", LabelEn: "NCAP body", Compare: CompareNone},
+		SpecField{Key: "ncap_year", Type: SpecTypeInteger, LabelTh: "ปีที่ทดสอบ", LabelEn: "NCAP year", Compare: CompareHigher},
+	),
+	inSection("comfort",
+		SpecField{Key: "seats", Type: SpecTypeInteger, Unit: "seats", LabelTh: "จำนวนที่นั่ง", LabelEn: "Seats", Compare: CompareNone},
+		SpecField{Key: "seat_material", Type: SpecTypeText, LabelTh: "วัสดุเบาะ", LabelEn: "Seat material", Compare: CompareNone},
+		SpecField{Key: "driver_seat_electric", Type: SpecTypeBoolean, LabelTh: "เบาะคนขับปรับไฟฟ้า", LabelEn: "Power driver seat", Compare: CompareHigher},
+		SpecField{Key: "passenger_seat_electric", Type: SpecTypeBoolean, LabelTh: "เบาะผู้โดยสารปรับไฟฟ้า", LabelEn: "Power passenger seat", Compare: CompareHigher},
+		SpecField{Key: "driver_seat_memory", Type: SpecTypeBoolean, LabelTh: "เบาะคนขับมีหน่วยความจำ", LabelEn: "Driver seat memory", Compare: CompareHigher},
+		SpecField{Key: "ventilated_seats_front", Type: SpecTypeBoolean, LabelTh: "เบาะหน้ามีระบบระบายอากาศ", LabelEn: "Ventilated front seats", Compare: CompareHigher},
+		SpecField{Key: "ventilated_seats_rear", Type: SpecTypeBoolean, LabelTh: "เบาะหลังมีระบบระบายอากาศ", LabelEn: "Ventilated rear seats", Compare: CompareHigher},
+		SpecField{Key: "heated_seats_front", Type: SpecTypeBoolean, LabelTh: "เบาะหน้าปรับอุ่น", LabelEn: "Heated front seats", Compare: CompareHigher},
+		SpecField{Key: "heated_seats_rear", Type: SpecTypeBoolean, LabelTh: "เบาะหลังปรับอุ่น", LabelEn: "Heated rear seats", Compare: CompareHigher},
+		SpecField{Key: "rear_seat_recline", Type: SpecTypeBoolean, LabelTh: "เบาะหลังปรับเอนได้", LabelEn: "Reclining rear seats", Compare: CompareHigher},
+		SpecField{Key: "ac_zones", Type: SpecTypeInteger, Unit: "zones", LabelTh: "โซนแอร์", LabelEn: "Climate zones", Compare: CompareHigher},
+		SpecField{Key: "rear_ac_vents", Type: SpecTypeBoolean, LabelTh: "ช่องแอร์ด้านหลัง", LabelEn: "Rear AC vents", Compare: CompareHigher},
+	),
+	inSection("infotainment",
+		SpecField{Key: "screen_size_inch", Type: SpecTypeDecimal, Unit: "in", LabelTh: "ขนาดจอกลาง", LabelEn: "Center screen size", Compare: CompareHigher},
+		SpecField{Key: "screen_type", Type: SpecTypeText, LabelTh: "ชนิดจอกลาง", LabelEn: "Screen type", Compare: CompareNone},
+		SpecField{Key: "digital_cluster", Type: SpecTypeBoolean, LabelTh: "หน้าปัดดิจิทัล", LabelEn: "Digital instrument cluster", Compare: CompareHigher},
+		SpecField{Key: "cluster_size_inch", Type: SpecTypeDecimal, Unit: "in", LabelTh: "ขนาดหน้าปัด", LabelEn: "Cluster size", Compare: CompareHigher},
+		SpecField{Key: "hud", Type: SpecTypeBoolean, LabelTh: "จอแสดงผลบนกระจกหน้า (HUD)", LabelEn: "Head-up display", Compare: CompareHigher},
+		SpecField{Key: "speaker_brand", Type: SpecTypeText, LabelTh: "ยี่ห้อเครื่องเสียง", LabelEn: "Audio brand", Compare: CompareNone},
+		SpecField{Key: "speaker_count", Type: SpecTypeInteger, Unit: "speakers", LabelTh: "จำนวนลำโพง", LabelEn: "Speakers", Compare: CompareHigher},
+		SpecField{Key: "apple_carplay", Type: SpecTypeBoolean, LabelTh: "Apple CarPlay", LabelEn: "Apple CarPlay", Compare: CompareHigher},
+		SpecField{Key: "android_auto", Type: SpecTypeBoolean, LabelTh: "Android Auto", LabelEn: "Android Auto", Compare: CompareHigher},
+		SpecField{Key: "wireless_carplay", Type: SpecTypeBoolean, LabelTh: "Apple CarPlay ไร้สาย", LabelEn: "Wireless CarPlay", Compare: CompareHigher},
+		SpecField{Key: "wireless_android_auto", Type: SpecTypeBoolean, LabelTh: "Android Auto ไร้สาย", LabelEn: "Wireless Android Auto", Compare: CompareHigher},
+		SpecField{Key: "wireless_phone_charging", Type: SpecTypeBoolean, LabelTh: "แท่นชาร์จโทรศัพท์ไร้สาย", LabelEn: "Wireless phone charging", Compare: CompareHigher},
+		SpecField{Key: "usb_c_ports", Type: SpecTypeInteger, Unit: "ports", LabelTh: "ช่อง USB-C", LabelEn: "USB-C ports", Compare: CompareHigher},
+		SpecField{Key: "usb_a_ports", Type: SpecTypeInteger, Unit: "ports", LabelTh: "ช่อง USB-A", LabelEn: "USB-A ports", Compare: CompareHigher},
+		SpecField{Key: "bluetooth", Type: SpecTypeText, LabelTh: "บลูทูธ", LabelEn: "Bluetooth", Compare: CompareNone},
+		SpecField{Key: "ota_update", Type: SpecTypeBoolean, LabelTh: "อัปเดตซอฟต์แวร์ผ่านอากาศ (OTA)", LabelEn: "Over-the-air updates", Compare: CompareHigher},
+	),
+	inSection("exterior",
+		SpecField{Key: "headlight_type", Type: SpecTypeText, LabelTh: "ชนิดไฟหน้า", LabelEn: "Headlights", Compare: CompareNone},
+		SpecField{Key: "drl", Type: SpecTypeBoolean, LabelTh: "ไฟเดย์ไลท์", LabelEn: "Daytime running lights", Compare: CompareHigher},
+		SpecField{Key: "auto_headlights", Type: SpecTypeBoolean, LabelTh: "ไฟหน้าเปิด-ปิดอัตโนมัติ", LabelEn: "Automatic headlights", Compare: CompareHigher},
+		SpecField{Key: "adaptive_headlights", Type: SpecTypeBoolean, LabelTh: "ไฟหน้าปรับทิศทางอัตโนมัติ", LabelEn: "Adaptive headlights", Compare: CompareHigher},
+		SpecField{Key: "fog_lights", Type: SpecTypeBoolean, LabelTh: "ไฟตัดหมอก", LabelEn: "Fog lights", Compare: CompareHigher},
+		SpecField{Key: "sunroof", Type: SpecTypeEnum, LabelTh: "หลังคาซันรูฟ", LabelEn: "Sunroof", Compare: CompareNone, Options: SunroofTypes},
+		SpecField{Key: "power_tailgate", Type: SpecTypeBoolean, LabelTh: "ประตูท้ายไฟฟ้า", LabelEn: "Power tailgate", Compare: CompareHigher},
+		SpecField{Key: "hands_free_tailgate", Type: SpecTypeBoolean, LabelTh: "ประตูท้ายเปิดด้วยการเตะ", LabelEn: "Hands-free tailgate", Compare: CompareHigher},
+		SpecField{Key: "keyless_entry", Type: SpecTypeBoolean, LabelTh: "กุญแจอัจฉริยะ", LabelEn: "Keyless entry", Compare: CompareHigher},
+		SpecField{Key: "push_start", Type: SpecTypeBoolean, LabelTh: "ปุ่มสตาร์ท", LabelEn: "Push-button start", Compare: CompareHigher},
+		SpecField{Key: "auto_folding_mirrors", Type: SpecTypeBoolean, LabelTh: "กระจกมองข้างพับอัตโนมัติ", LabelEn: "Auto-folding mirrors", Compare: CompareHigher},
+		SpecField{Key: "rain_sensing_wipers", Type: SpecTypeBoolean, LabelTh: "ที่ปัดน้ำฝนอัตโนมัติ", LabelEn: "Rain-sensing wipers", Compare: CompareHigher},
+		SpecField{Key: "roof_rails", Type: SpecTypeBoolean, LabelTh: "ราวหลังคา", LabelEn: "Roof rails", Compare: CompareHigher},
+	),
+	inSection("warranty",
+		SpecField{Key: "warranty_years", Type: SpecTypeInteger, Unit: "years", LabelTh: "รับประกันตัวรถ (ปี)", LabelEn: "Warranty (years)", Compare: CompareHigher},
+		SpecField{Key: "warranty_km", Type: SpecTypeInteger, Unit: "km", LabelTh: "รับประกันตัวรถ (กม.)", LabelEn: "Warranty (km)", Compare: CompareHigher},
+		SpecField{Key: "battery_warranty_years", Type: SpecTypeInteger, Unit: "years", LabelTh: "รับประกันแบตเตอรี่ (ปี)", LabelEn: "Battery warranty (years)", Compare: CompareHigher},
+		SpecField{Key: "battery_warranty_km", Type: SpecTypeInteger, Unit: "km", LabelTh: "รับประกันแบตเตอรี่ (กม.)", LabelEn: "Battery warranty (km)", Compare: CompareHigher},
+	),
+)
+
+// SpecFieldByKey - lookup into SpecFields
+var SpecFieldByKey = func() map[string]SpecField {
+	byKey := make(map[string]SpecField, len(SpecFields))
+	for _, f := range SpecFields {
+		byKey[f.Key] = f
+	}
+	return byKey
+}()
+
+func inSection(section string, fields ...SpecField) []SpecField {
+	for i := range fields {
+		fields[i].Section = section
+	}
+	return fields
+}
+
+func concatSections(sections ...[]SpecField) []SpecField {
+	var all []SpecField
+	for _, s := range sections {
+		all = append(all, s...)
+	}
+	return all
+}
@@ -11,4 +11,7 @@ type User struct {
 	GoogleID     string    `json:"google_id,omitempty"`
 	AvatarURL    string    `json:"avatar_url,omitempty"`
 	CreatedAt    time.Time `json:"created_at"`
+	// Loaded on demand by middleware.LoadAccess; every account has "user"
+	Roles       []string `json:"roles,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
 }
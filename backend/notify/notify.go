@@ -0,0 +1,48 @@
+package notify
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+)
+
+// PriceDrop - a price alert that fired
+type PriceDrop struct {
+	AlertID           int       `json:"alert_id"`
+	UserID            int       `json:"user_id"`
+	VariantID         int       `json:"variant_id"`
+	VariantName       string    `json:"variant_name"`
+	BrandName         string    `json:"brand_name"`
+	ModelName         string    `json:"model_name"`
+	PriceBaht         float64   `json:"price_baht"`
+	PreviousPriceBaht *float64  `json:"previous_price_baht"`
+	ThresholdBaht     float64   `json:"threshold_baht"`
+	DetectedAt        time.Time `json:"detected_at"`
+}
+
+// Notifier delivers alerts to users. Implementations must be safe for
+// concurrent use; a returned error means the alert is retried later.
+type Notifier interface {
+	NotifyPriceDrop(ctx context.Context, n PriceDrop) error
+}
+
+// LogNotifier writes alerts to the server log, for development
+type LogNotifier struct{}
+
+func (LogNotifier) NotifyPriceDrop(ctx context.Context, n PriceDrop) error {
+	log.Printf("🔔 Price alert %d: user %d, %s %s %s now %.0f THB (threshold %.0f)",
+		n.AlertID, n.UserID, n.BrandName, n.ModelName, n.VariantName, n.PriceBaht, n.ThresholdBaht)
+	return nil
+}
+
+// FromEnv - a WebhookNotifier when ALERT_WEBHOOK_URL is set (signed with
+// ALERT_WEBHOOK_SECRET), otherwise a LogNotifier
+func FromEnv() Notifier {
+	url := os.Getenv("ALERT_WEBHOOK_URL")
+	if url == "" {
+		log.Println("⚠️  ALERT_WEBHOOK_URL is not set, price alerts are only logged")
+		return LogNotifier{}
+	}
+	return NewWebhookNotifier(url, os.Getenv("ALERT_WEBHOOK_SECRET"))
+}
@@ -0,0 +1,194 @@
+package rbac
+
+import (
+	"comparebuddy-backend/config"
+	"database/sql"
+	"errors"
+	"log"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Roles
+const (
+	RoleUser   = "user"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+// Permissions checked by middleware.RequirePermission; role_permissions in
+// migrations/rbac.sql decides which role has which
+const (
+	PermCatalogWrite = "catalog.write"
+	PermMediaUpload  = "media.upload"
+	PermRolesManage  = "roles.manage"
+	PermAuditRead    = "audit.read"
+)
+
+var (
+	ErrUnknownRole  = errors.New("unknown role")
+	ErrImplicitRole = errors.New("every account has the user role")
+	ErrLastAdmin    = errors.New("cannot revoke the last admin")
+)
+
+// Access - roles and permissions of one user, sorted
+func Access(userID int) (roles []string, permissions []string, err error) {
+	rows, err := config.DB.Query(
+		`SELECT r.name, p.name
+		FROM user_roles ur
+		JOIN roles r ON ur.role_id = r.id
+		LEFT JOIN role_permissions rp ON rp.role_id = r.id
+		LEFT JOIN permissions p ON rp.permission_id = p.id
+		WHERE ur.user_id = ?`,
+		userID,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	roleSet := map[string]bool{RoleUser: true}
+	permSet := map[string]bool{}
+	for rows.Next() {
+		var role string
+		var perm *string
+		if err := rows.Scan(&role, &perm); err != nil {
+			return nil, nil, err
+		}
+		roleSet[role] = true
+		if perm != nil {
+			permSet[*perm] = true
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	return sortedKeys(roleSet), sortedKeys(permSet), nil
+}
+
+// Grant - gives userID the role; false when the user already had it
+func Grant(userID int, role string, grantedBy *int) (bool, error) {
+	if role == RoleUser {
+		return false, ErrImplicitRole
+	}
+	roleID, err := roleID(role)
+	if err != nil {
+		return false, err
+	}
+	result, err := config.DB.Exec(
+		"INSERT IGNORE INTO user_roles (user_id, role_id, granted_by) VALUES (?, ?, ?)",
+		userID, roleID, grantedBy,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, _ := result.RowsAffected()
+	return n > 0, nil
+}
+
+// Revoke - takes the role away; false when the user did not have it.
+// The last admin cannot be revoked, so the API never locks itself out.
+func Revoke(userID int, role string) (bool, error) {
+	if role == RoleUser {
+		return false, ErrImplicitRole
+	}
+	roleID, err := roleID(role)
+	if err != nil {
+		return false, err
+	}
+
+	tx, err := config.DB.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if role == RoleAdmin {
+		var admins int
+		var isAdmin bool
+		err := tx.QueryRow(
+			"SELECT COUNT(*), COALESCE(SUM(user_id = ?), 0) > 0 FROM user_roles WHERE role_id = ? FOR UPDATE",
+			userID, roleID,
+		).Scan(&admins, &isAdmin)
+		if err != nil {
+			return false, err
+		}
+		if isAdmin && admins == 1 {
+			return false, ErrLastAdmin
+		}
+	}
+
+	result, err := tx.Exec("DELETE FROM user_roles WHERE user_id = ? AND role_id = ?", userID, roleID)
+	if err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	n, _ := result.RowsAffected()
+	return n > 0, nil
+}
+
+// Bootstrap - grants admin to the account whose email is BOOTSTRAP_ADMIN as
+// long as nobody is admin yet. Once there is an admin, roles are managed
+// through the API and the variable is ignored.
+//
+// Registration does not verify email addresses, so whoever registers the
+// address first gets admin: register the account yourself, check that it is
+// yours, and only then set the variable and restart.
+func Bootstrap() {
+	email := strings.TrimSpace(os.Getenv("BOOTSTRAP_ADMIN"))
+	if email == "" {
+		return
+	}
+
+	var admins int
+	err := config.DB.QueryRow(
+		"SELECT COUNT(*) FROM user_roles ur JOIN roles r ON ur.role_id = r.id WHERE r.name = ?",
+		RoleAdmin,
+	).Scan(&admins)
+	if err != nil {
+		log.Println("⚠️  Admin bootstrap failed:", err)
+		return
+	}
+	if admins > 0 {
+		return
+	}
+
+	var userID int
+	var username string
+	err = config.DB.QueryRow("SELECT id, username FROM users WHERE email = ?", email).Scan(&userID, &username)
+	if err == sql.ErrNoRows {
+		log.Printf("⚠️  BOOTSTRAP_ADMIN %q has no account yet; register it and restart", email)
+		return
+	}
+	if err != nil {
+		log.Println("⚠️  Admin bootstrap failed:", err)
+		return
+	}
+
+	if _, err := Grant(userID, RoleAdmin, nil); err != nil {
+		log.Println("⚠️  Admin bootstrap failed:", err)
+		return
+	}
+	log.Printf("✅ Granted admin to %s (%s, user %d)", email, username, userID)
+}
+
+func roleID(role string) (int, error) {
+	var id int
+	err := config.DB.QueryRow("SELECT id FROM roles WHERE name = ?", role).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, ErrUnknownRole
+	}
+	return id, err
+}
+
+func sortedKeys(set map[string]bool) []string {
+	out := make([]string, 0, len(set))
+	for k := range set {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
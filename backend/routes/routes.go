@@ -2,12 +2,22 @@ package routes
 
 import (
 	"comparebuddy-backend/handlers"
+	"comparebuddy-backend/middleware"
+	"comparebuddy-backend/rbac"
 	"github.com/gofiber/fiber/v2"
 )
 
 func SetupRoutes(app *fiber.App) {
 	api := app.Group("/api")
 	
+	// Image uploads, registered first: they are exempt from the body limit below
+	upload := middleware.RequirePermission(rbac.PermMediaUpload)
+	api.Post("/cars/brands/:id/logo", middleware.RequireAuth(), upload, handlers.UploadBrandLogo)
+	api.Post("/cars/variants/:id/images", middleware.RequireAuth(), upload, handlers.UploadVariantImage)
+	
+	// Every other route keeps Fiber's default body limit
+	api.Use(middleware.BodyLimit(fiber.DefaultBodyLimit))
+	
 	// Categories
 	api.Get("/categories/main", handlers.GetMainCategories)
 	api.Get("/categories/sub", handlers.GetSubCategories)
@@ -22,17 +32,85 @@ func SetupRoutes(app *fiber.App) {
 	auth.Post("/register", handlers.Register)
 	auth.Post("/login", handlers.Login)
 	auth.Post("/google", handlers.GoogleLogin)
+	auth.Post("/refresh", handlers.RefreshSession)
+	auth.Post("/logout", handlers.Logout)
+
+	// Current user (requires a bearer access token)
+	me := api.Group("/me", middleware.RequireAuth())
+	me.Get("/", handlers.GetMe)
+	me.Put("/", handlers.UpdateMe)
+	me.Get("/favorites", handlers.GetFavorites)
+	me.Post("/favorites", handlers.AddFavorite)
+	me.Delete("/favorites/:variant_id", handlers.RemoveFavorite)
+	me.Get("/alerts", handlers.GetPriceAlerts)
+	me.Post("/alerts", handlers.CreatePriceAlert)
+	me.Delete("/alerts/:id", handlers.DeletePriceAlert)
+	me.Get("/comparisons", handlers.GetComparisons)
+	me.Post("/comparisons", handlers.CreateComparison)
+	me.Put("/comparisons/order", handlers.ReorderComparisons)
+	me.Put("/comparisons/:id", handlers.UpdateComparison)
+	me.Delete("/comparisons/:id", handlers.DeleteComparison)
+	me.Get("/comparisons/:id/run", handlers.RunComparison)
+	me.Post("/comparisons/:id/share", handlers.ShareComparison)
+	me.Delete("/comparisons/:id/share", handlers.UnshareComparison)
+
+	// Shared comparisons (public)
+	api.Get("/compare/s/:slug", handlers.GetSharedComparison)
 
 	// Cars
-	cars := api.Group("/cars")
+	cars := api.Group("/cars", middleware.OptionalAuth())
 	cars.Get("/brands", handlers.GetCarBrands)
 	cars.Get("/brands/:id", handlers.GetCarBrandByID)
 	cars.Get("/models", handlers.GetCarModels)
 	cars.Get("/models/:id", handlers.GetCarModelByID)
 	cars.Get("/variants/:id", handlers.GetCarVariantByID)
+	cars.Get("/variants/:id/images", handlers.GetVariantImages)
+	cars.Get("/variants/:id/colors", handlers.GetVariantColors)
+	cars.Get("/variants/:id/similar", handlers.GetSimilarVariants)
+	cars.Get("/variants/:id/installment", handlers.GetVariantInstallment)
+	cars.Get("/variants/:id/price-history", handlers.GetVariantPriceHistory)
 	cars.Get("/compare", handlers.CompareCarVariants)
+	cars.Get("/tco", handlers.GetVariantTCO)
 	cars.Get("/search", handlers.SearchCars)
+	cars.Get("/suggest", handlers.SuggestCars)
 	cars.Get("/browse", handlers.BrowseCarVariants)
+	cars.Get("/spec-fields", handlers.GetSpecFields)
+
+	// Car media management
+	cars.Put("/variants/:id/images/order", middleware.RequireAuth(), upload, handlers.ReorderVariantImages)
+	cars.Delete("/images/:id", middleware.RequireAuth(), upload, handlers.DeleteCarImage)
+
+	// Catalog editing, audit log and role management, by permission
+	admin := api.Group("/admin", middleware.RequireAuth())
+	catalogWrite := middleware.RequirePermission(rbac.PermCatalogWrite)
+	manageRoles := middleware.RequirePermission(rbac.PermRolesManage)
+	readAudit := middleware.RequirePermission(rbac.PermAuditRead)
+	admin.Post("/cars/brands", catalogWrite, handlers.CreateCarBrand)
+	admin.Put("/cars/brands/:id", catalogWrite, handlers.ReplaceCarBrand)
+	admin.Patch("/cars/brands/:id", catalogWrite, handlers.UpdateCarBrand)
+	admin.Delete("/cars/brands/:id", catalogWrite, handlers.DeleteCarBrand)
+	admin.Post("/cars/models", catalogWrite, handlers.CreateCarModel)
+	admin.Put("/cars/models/:id", catalogWrite, handlers.ReplaceCarModel)
+	admin.Patch("/cars/models/:id", catalogWrite, handlers.UpdateCarModel)
+	admin.Delete("/cars/models/:id", catalogWrite, handlers.DeleteCarModel)
+	admin.Post("/cars/variants", catalogWrite, handlers.CreateCarVariant)
+	admin.Put("/cars/variants/:id", catalogWrite, handlers.ReplaceCarVariant)
+	admin.Patch("/cars/variants/:id", catalogWrite, handlers.UpdateCarVariant)
+	admin.Delete("/cars/variants/:id", catalogWrite, handlers.DeleteCarVariant)
+	admin.Get("/cars/variants/:id/history", readAudit, handlers.GetVariantHistory)
+	admin.Post("/items", catalogWrite, handlers.CreateItem)
+	admin.Put("/items/:id", catalogWrite, handlers.ReplaceItem)
+	admin.Patch("/items/:id", catalogWrite, handlers.UpdateItem)
+	admin.Delete("/items/:id", catalogWrite, handlers.DeleteItem)
+	admin.Post("/categories", catalogWrite, handlers.CreateCategory)
+	admin.Put("/categories/:id", catalogWrite, handlers.ReplaceCategory)
+	admin.Patch("/categories/:id", catalogWrite, handlers.UpdateCategory)
+	admin.Delete("/categories/:id", catalogWrite, handlers.DeleteCategory)
+	admin.Get("/audit", readAudit, handlers.GetAuditLog)
+	admin.Get("/roles", manageRoles, handlers.GetRoles)
+	admin.Get("/users/:id/roles", manageRoles, handlers.GetUserRoles)
+	admin.Post("/users/:id/roles", manageRoles, handlers.GrantUserRole)
+	admin.Delete("/users/:id/roles/:role", manageRoles, handlers.RevokeUserRole)
 
 	// Health check
 	api.Get("/health", func(c *fiber.Ctx) error {
@@ -0,0 +1,227 @@
+package search
+
+import (
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Field weights: a model name hit says more about intent than a trim name hit
+const (
+	WeightModel   = 3.0
+	WeightBrand   = 2.0
+	WeightVariant = 1.0
+)
+
+// Per-token match scores, before the field weight is applied
+const (
+	scoreExact  = 3.0
+	scorePrefix = 2.0
+	scoreInfix  = 1.0
+)
+
+// minCoverage - share of query tokens a document must match to be returned
+const minCoverage = 0.5
+
+// minThaiSimilarity - bigram similarity at which a Thai token counts as a match
+const minThaiSimilarity = 0.6
+
+// Field - one searchable text of a document
+type Field struct {
+	Text   string
+	Weight float64
+}
+
+// Document - one searchable entity, identified by Kind and ID.
+// Kind and Label are only needed for suggestions.
+type Document struct {
+	Kind   string
+	ID     int
+	Label  string
+	Fields []Field
+}
+
+// Hit - a matching document and its relevance score
+type Hit struct {
+	ID    int     `json:"id"`
+	Score float64 `json:"score"`
+}
+
+type indexedField struct {
+	weight  float64
+	tokens  []string
+	bigrams [][]string
+	compact string
+}
+
+type indexedDoc struct {
+	kind    string
+	id      int
+	label   string
+	fields  []indexedField
+	compact string
+	tokens  int
+}
+
+// Loader returns every document to index
+type Loader func() ([]Document, error)
+
+// Index - in-memory search index, rebuilt from its Loader once it is older than ttl
+type Index struct {
+	mu      sync.RWMutex
+	load    Loader
+	ttl     time.Duration
+	docs    []indexedDoc
+	builtAt time.Time
+}
+
+// NewIndex - an empty index; it is built on first use
+func NewIndex(ttl time.Duration, load Loader) *Index {
+	return &Index{load: load, ttl: ttl}
+}
+
+// Invalidate - forces a rebuild on the next search, e.g. after catalog writes
+func (ix *Index) Invalidate() {
+	ix.mu.Lock()
+	ix.builtAt = time.Time{}
+	ix.mu.Unlock()
+}
+
+// refresh - rebuilds the index when stale. A failed rebuild keeps serving the
+// previous index and only returns an error when there is nothing to serve.
+func (ix *Index) refresh() error {
+	ix.mu.RLock()
+	fresh := !ix.builtAt.IsZero() && time.Since(ix.builtAt) < ix.ttl
+	ix.mu.RUnlock()
+	if fresh {
+		return nil
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if !ix.builtAt.IsZero() && time.Since(ix.builtAt) < ix.ttl {
+		return nil
+	}
+
+	docs, err := ix.load()
+	if err != nil {
+		if ix.docs != nil {
+			log.Println("⚠️  Search index refresh failed, serving previous index:", err)
+			return nil
+		}
+		return err
+	}
+
+	ix.docs = make([]indexedDoc, len(docs))
+	for i, d := range docs {
+		ix.docs[i] = indexDocument(d)
+	}
+	ix.builtAt = time.Now()
+	return nil
+}
+
+func indexDocument(d Document) indexedDoc {
+	doc := indexedDoc{kind: d.Kind, id: d.ID, label: d.Label, fields: make([]indexedField, 0, len(d.Fields))}
+	var compact []string
+	for _, f := range d.Fields {
+		tokens := Tokenize(f.Text)
+		if len(tokens) == 0 {
+			continue
+		}
+		field := indexedField{weight: f.Weight, tokens: tokens, compact: strings.Join(tokens, "")}
+		for _, t := range tokens {
+			if isThaiToken(t) {
+				field.bigrams = append(field.bigrams, bigrams(t))
+			} else {
+				field.bigrams = append(field.bigrams, nil)
+			}
+		}
+		doc.fields = append(doc.fields, field)
+		doc.tokens += len(tokens)
+		compact = append(compact, field.compact)
+	}
+	doc.compact = strings.Join(compact, "")
+	return doc
+}
+
+// Search - documents matching q, best first. Every query token is scored
+// against every field (exact token > prefix > substring, Thai tokens by bigram
+// similarity); documents matching fewer than half the tokens are dropped.
+func (ix *Index) Search(q string) ([]Hit, error) {
+	if err := ix.refresh(); err != nil {
+		return nil, err
+	}
+
+	query := Tokenize(q)
+	if len(query) == 0 {
+		return []Hit{}, nil
+	}
+	queryBigrams := make([][]string, len(query))
+	for i, t := range query {
+		if isThaiToken(t) {
+			queryBigrams[i] = bigrams(t)
+		}
+	}
+	phrase := strings.Join(query, "")
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	hits := []Hit{}
+	for _, doc := range ix.docs {
+		matched := 0
+		score := 0.0
+		for i, t := range query {
+			best := 0.0
+			for _, f := range doc.fields {
+				if s := matchToken(t, queryBigrams[i], f) * f.weight; s > best {
+					best = s
+				}
+			}
+			if best > 0 {
+				matched++
+				score += best
+			}
+		}
+
+		coverage := float64(matched) / float64(len(query))
+		if matched == 0 || coverage < minCoverage {
+			continue
+		}
+		// "byd atto3" against "BYD" + "ATTO 3": the whole query appears verbatim
+		if len(query) > 1 && strings.Contains(doc.compact, phrase) {
+			score += scoreExact
+		}
+		hits = append(hits, Hit{ID: doc.id, Score: score * coverage * coverage})
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID < hits[j].ID
+	})
+	return hits, nil
+}
+
+func matchToken(t string, tBigrams []string, f indexedField) float64 {
+	best := 0.0
+	for i, token := range f.tokens {
+		switch {
+		case token == t:
+			return scoreExact
+		case strings.HasPrefix(token, t):
+			best = scorePrefix
+		case tBigrams != nil && f.bigrams[i] != nil:
+			if sim := bigramSimilarity(tBigrams, f.bigrams[i]); sim >= minThaiSimilarity && sim*scorePrefix > best {
+				best = sim * scorePrefix
+			}
+		}
+	}
+	if best == 0 && len([]rune(t)) >= 2 && strings.Contains(f.compact, t) {
+		best = scoreInfix
+	}
+	return best
+}
@@ -0,0 +1,93 @@
+package search
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func testIndex() *Index {
+	return NewIndex(0, func() ([]Document, error) {
+		return []Document{
+			{ID: 1, Fields: []Field{{"BYD", WeightBrand}, {"ATTO 3", WeightModel}, {"Extended", WeightVariant}}},
+			{ID: 2, Fields: []Field{{"BYD", WeightBrand}, {"Seal", WeightModel}, {"Performance AWD", WeightVariant}}},
+			{ID: 3, Fields: []Field{{"Toyota", WeightBrand}, {"โตโยต้า", WeightBrand}, {"Yaris ATIV", WeightModel}}},
+			{ID: 4, Fields: []Field{{"Tesla", WeightBrand}, {"Model 3", WeightModel}}},
+		}, nil
+	})
+}
+
+func hitIDs(hits []Hit) []int {
+	ids := []int{}
+	for _, h := range hits {
+		ids = append(ids, h.ID)
+	}
+	return ids
+}
+
+func TestIndexSearch(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"", []int{}},
+		{"atto", []int{1}},
+		{"byd", []int{1, 2}},
+		// Model field outweighs the brand field
+		{"seal byd", []int{2, 1}},
+		// "atto3" splits into two tokens, so a Seal matches only a third
+		{"byd atto3", []int{1}},
+		{"yar", []int{3}},
+		{"ttl", []int{}},
+		{"โตโยตา", []int{3}},
+		// Half the tokens matching is enough, fewer is not
+		{"byd xyz", []int{1, 2}},
+		{"atto xyz qqq", []int{}},
+		// "3" matches both ATTO 3 and Model 3; the second token decides
+		{"model 3", []int{4, 1}},
+	}
+	ix := testIndex()
+	for _, tt := range tests {
+		hits, err := ix.Search(tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hitIDs(hits); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestIndexSearchCoverage(t *testing.T) {
+	ix := testIndex()
+	full, _ := ix.Search("byd atto")
+	half, _ := ix.Search("atto xyz")
+	if len(full) == 0 || len(half) == 0 {
+		t.Fatalf("Search = %v, %v, want hits for both", full, half)
+	}
+	if half[0].Score >= full[0].Score {
+		t.Errorf("half coverage score %g >= full coverage score %g", half[0].Score, full[0].Score)
+	}
+}
+
+func TestIndexServesPreviousOnFailure(t *testing.T) {
+	calls := 0
+	ix := NewIndex(0, func() ([]Document, error) {
+		calls++
+		if calls > 1 {
+			return nil, errors.New("database down")
+		}
+		return []Document{{ID: 7, Fields: []Field{{"Volvo", WeightBrand}}}}, nil
+	})
+	for i := 0; i < 2; i++ {
+		hits, err := ix.Search("volvo")
+		if err != nil || !reflect.DeepEqual(hitIDs(hits), []int{7}) {
+			t.Errorf("Search #%d = %v, %v, want [7]", i+1, hitIDs(hits), err)
+		}
+	}
+
+	empty := NewIndex(0, func() ([]Document, error) { return nil, errors.New("database down") })
+	if _, err := empty.Search("volvo"); err == nil {
+		t.Error("Search with no index and a failing loader returned no error")
+	}
+}
@@ -0,0 +1,113 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Character classes used to split text into tokens. A token ends wherever the
+// class changes, so "ATTO3" and "atto 3" both become ["atto", "3"], and Thai
+// text glued to Latin text ("BYDบีวายดี") is split as well.
+const (
+	classSeparator = iota
+	classLatin
+	classDigit
+	classThai
+)
+
+func isThai(r rune) bool {
+	return r >= 0x0E01 && r <= 0x0E5B
+}
+
+// foldRune - lowercases and maps full-width ASCII and Thai digits onto plain ASCII
+func foldRune(r rune) rune {
+	switch {
+	case r >= 0xFF01 && r <= 0xFF5E:
+		r -= 0xFEE0
+	case r >= 0x0E50 && r <= 0x0E59:
+		r = '0' + (r - 0x0E50)
+	}
+	return unicode.ToLower(r)
+}
+
+func runeClass(r rune) int {
+	switch {
+	case r >= '0' && r <= '9':
+		return classDigit
+	case isThai(r):
+		return classThai
+	case unicode.IsLetter(r):
+		return classLatin
+	}
+	return classSeparator
+}
+
+// Tokenize - normalized tokens of s: lowercase, punctuation and spacing removed,
+// split on script and letter/digit boundaries
+func Tokenize(s string) []string {
+	var tokens []string
+	var cur strings.Builder
+	prev := classSeparator
+
+	for _, r := range s {
+		r = foldRune(r)
+		class := runeClass(r)
+		if class != prev && cur.Len() > 0 {
+			tokens = append(tokens, cur.String())
+			cur.Reset()
+		}
+		if class != classSeparator {
+			cur.WriteRune(r)
+		}
+		prev = class
+	}
+	if cur.Len() > 0 {
+		tokens = append(tokens, cur.String())
+	}
+	return tokens
+}
+
+// Normalize - s with every separator removed, e.g. "BYD Atto-3" -> "bydatto3"
+func Normalize(s string) string {
+	return strings.Join(Tokenize(s), "")
+}
+
+// bigrams - overlapping rune pairs; Thai is written without spaces between
+// words, so Thai tokens are matched on bigrams instead of whole words
+func bigrams(token string) []string {
+	runes := []rune(token)
+	if len(runes) < 2 {
+		return []string{token}
+	}
+	out := make([]string, len(runes)-1)
+	for i := range out {
+		out[i] = string(runes[i : i+2])
+	}
+	return out
+}
+
+// bigramSimilarity - Dice coefficient of the bigram sets of a and b
+func bigramSimilarity(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	set := map[string]int{}
+	for _, g := range b {
+		set[g]++
+	}
+	shared := 0
+	for _, g := range a {
+		if set[g] > 0 {
+			set[g]--
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(a)+len(b))
+}
+
+func isThaiToken(token string) bool {
+	for _, r := range token {
+		return isThai(r)
+	}
+	return false
+}
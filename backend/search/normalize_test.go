@@ -0,0 +1,74 @@
+package search
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"BYD ATTO 3", []string{"byd", "atto", "3"}},
+		{"byd atto3", []string{"byd", "atto", "3"}},
+		{"Atto-3", []string{"atto", "3"}},
+		{"Model  Y!", []string{"model", "y"}},
+		{"ＢＹＤ", []string{"byd"}},
+		{"รุ่น๒๐๒๕", []string{"รุ่น", "2025"}},
+		{"BYDบีวายดี", []string{"byd", "บีวายดี"}},
+		{"  --  ", nil},
+	}
+	for _, tt := range tests {
+		if got := Tokenize(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Tokenize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	for _, in := range []string{"BYD Atto-3", "byd atto3", "BYD ATTO 3"} {
+		if got := Normalize(in); got != "bydatto3" {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, "bydatto3")
+		}
+	}
+}
+
+func TestBigrams(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"ก", []string{"ก"}},
+		{"กข", []string{"กข"}},
+		{"โตโย", []string{"โต", "ตโ", "โย"}},
+	}
+	for _, tt := range tests {
+		if got := bigrams(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("bigrams(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestBigramSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"โตโยต้า", "โตโยต้า", 1},
+		{"โตโยต้า", "ฮอนด้า", 1.0 / 5.5},
+		{"abcd", "wxyz", 0},
+		// Repeated bigrams only count as often as they occur on both sides
+		{"aaa", "aa", 2.0 / 3},
+	}
+	for _, tt := range tests {
+		got := bigramSimilarity(bigrams(tt.a), bigrams(tt.b))
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("bigramSimilarity(%q, %q) = %g, want %g", tt.a, tt.b, got, tt.want)
+		}
+	}
+	if got := bigramSimilarity(nil, bigrams("ab")); got != 0 {
+		t.Errorf("bigramSimilarity(nil, ...) = %g, want 0", got)
+	}
+}
@@ -0,0 +1,303 @@
+package search
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Interpretation - what ParseQuery understood from free text
+type Interpretation struct {
+	// Filters - browse filter parameters, e.g. max_price_baht=1500000, body_type=suv
+	Filters map[string]string `json:"filters"`
+	// Text - what is left to match against names once the filters are taken out
+	Text string `json:"text"`
+	// Matched - the phrases that became filters, in the order they were found
+	Matched []string `json:"matched"`
+}
+
+// keywordFilter - a phrase that maps onto a filter value. Phrases are tried in
+// order, so longer phrases ("plug-in hybrid") must come before shorter ones ("hybrid").
+type keywordFilter struct {
+	re    *regexp.Regexp
+	param string
+	value string
+}
+
+func keywords(param string, pairs ...string) []keywordFilter {
+	out := make([]keywordFilter, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		// English phrases need word boundaries ("ev" must not match "every");
+		// Thai is written without spaces, so Thai phrases match anywhere
+		pattern := pairs[i]
+		if !isThaiToken(pattern) {
+			pattern = `\b` + pattern + `\b`
+		}
+		out = append(out, keywordFilter{re: regexp.MustCompile(pattern), param: param, value: pairs[i+1]})
+	}
+	return out
+}
+
+var powertrainKeywords = keywords("powertrain_type",
+	`plug[- ]?in(?: hybrid)?`, "PHEV",
+	`phev`, "PHEV",
+	`ปลั๊กอินไฮบริด`, "PHEV",
+	`mild[- ]hybrid`, "MHEV",
+	`mhev`, "MHEV",
+	`hybrid`, "HEV",
+	`hev`, "HEV",
+	`ไฮบริด`, "HEV",
+	`electric`, "BEV",
+	`bev`, "BEV",
+	`evs?`, "BEV",
+	`รถไฟฟ้า`, "BEV",
+	`ไฟฟ้า`, "BEV",
+	`อีวี`, "BEV",
+	`petrol|gasoline|diesel|ice`, "ICE",
+	`เบนซิน|ดีเซล|น้ำมัน`, "ICE",
+)
+
+var bodyTypeKeywords = keywords("body_type",
+	`suvs?`, "suv",
+	`เอสยูวี`, "suv",
+	`crossovers?`, "crossover",
+	`ครอสโอเวอร์`, "crossover",
+	`sedans?|saloons?`, "sedan",
+	`ซีดาน|เก๋ง`, "sedan",
+	`hatchbacks?|hatch`, "hatchback",
+	`แฮทช์แบ็ก|แฮทช์แบค`, "hatchback",
+	`mpvs?|minivans?`, "mpv",
+	`pick[- ]?ups?|pickup trucks?`, "pickup",
+	`กระบะ|ปิคอัพ|ปิกอัพ`, "pickup",
+	`coupes?`, "coupe",
+	`คูเป้`, "coupe",
+	`wagons?|estates?`, "wagon",
+	`vans?`, "van",
+	`รถตู้`, "van",
+)
+
+const (
+	numberPattern = `(\d{1,3}(?:,\d{3})+|\d+(?:\.\d+)?)`
+	upperPattern  = `under|below|less than|cheaper than|max(?:imum)?|up to|within|budget|not more than|<=?|ไม่เกิน|ต่ำกว่า|น้อยกว่า|ไม่ถึง|งบประมาณ|งบ|ภายใน`
+	lowerPattern  = `over|above|more than|at least|min(?:imum)?|from|starting|>=?|ไม่ต่ำกว่า|อย่างน้อย|มากกว่า|เกิน|ตั้งแต่`
+	// postfix "or more": "1 ล้านขึ้นไป", "400km+"
+	orMorePattern = `\s*(?:ขึ้นไป|\+|or more|and (?:up|above)|plus)`
+)
+
+var (
+	rangeRe = regexp.MustCompile(`(?:(` + upperPattern + `)|(` + lowerPattern + `))?\s*(?:range(?: of)?\s*|วิ่งได้\s*|ระยะทาง\s*)?` +
+		numberPattern + `\s*(?:km|กม\.?|กิโลเมตร|กิโล)(?:\s*range)?(` + orMorePattern + `)?`)
+	seatsRe       = regexp.MustCompile(`(\d+)\s*-?\s*(?:(?:seaters?|seats?)\b|ที่นั่ง)`)
+	seatsBeforeRe = regexp.MustCompile(`(?:seats?\b|ที่นั่ง)\s*(\d+)`)
+	// priceUnit - the unit after an amount, with any space before it. The bare
+	// "m" and "k" only count when they touch the number ("1.5m", "800k"), so
+	// "x3 m sport" is not three million baht.
+	priceUnit = `(\s*(?:ล้านบาท|ล้าน|แสน|หมื่น|พัน|บาท)|\s*(?:million|mil|mb|thousand|baht|thb)\b|[mk]\b)?`
+	betweenRe = regexp.MustCompile(`(?:between|ระหว่าง|ราคา)?\s*` + numberPattern + priceUnit + `\s*(?:-|–|to|and|ถึง)\s*` + numberPattern + priceUnit)
+	priceRe   = regexp.MustCompile(`(?:(` + upperPattern + `)|(` + lowerPattern + `))?\s*(?:price|ราคา)?\s*` + numberPattern + priceUnit + `(` + orMorePattern + `)?`)
+)
+
+var priceMultipliers = map[string]float64{
+	"ล้านบาท": 1e6, "ล้าน": 1e6, "million": 1e6, "mil": 1e6, "mb": 1e6, "m": 1e6,
+	"แสน": 1e5, "หมื่น": 1e4,
+	"thousand": 1e3, "k": 1e3, "พัน": 1e3,
+	"บาท": 1, "baht": 1, "thb": 1,
+}
+
+// minBarePrice - numbers without a currency unit below this are not prices ("model 3")
+const minBarePrice = 10000
+
+// stopwords - filler left over after filters are taken out
+var stopwords = map[string]bool{
+	"a": true, "an": true, "the": true, "with": true, "and": true, "or": true, "for": true,
+	"car": true, "cars": true, "price": true, "range": true, "of": true, "in": true, "i": true,
+	"want": true, "need": true, "looking": true, "show": true, "me": true, "find": true,
+	"รถ": true, "ราคา": true, "บาท": true, "และ": true, "ที่": true, "มี": true, "แบบ": true,
+	"คัน": true, "หา": true, "อยาก": true, "ได้": true, "ครับ": true, "ค่ะ": true, "คะ": true,
+}
+
+// ParseQuery - extracts browse filters (price bounds, powertrain, body type, seats,
+// range) from free text in Thai or English, e.g. "EV SUV under 1.5 million with
+// 400km range" or "รถไฟฟ้า ไม่เกิน 1 ล้าน". Bare amounts are read as a budget (max).
+func ParseQuery(q string) Interpretation {
+	p := &queryParser{text: " " + strings.ToLower(q) + " ", filters: map[string]string{}, matched: []string{}}
+
+	p.parseRange()
+	p.parseSeats()
+	p.parseKeywords(powertrainKeywords)
+	p.parseKeywords(bodyTypeKeywords)
+	p.parsePrice()
+
+	var rest []string
+	for _, t := range strings.Fields(p.text) {
+		if !stopwords[t] {
+			rest = append(rest, t)
+		}
+	}
+
+	return Interpretation{Filters: p.filters, Text: strings.Join(rest, " "), Matched: p.matched}
+}
+
+type queryParser struct {
+	text    string
+	filters map[string]string
+	matched []string
+}
+
+// consume - records a matched phrase and blanks it out of the remaining text
+func (p *queryParser) consume(start, end int) {
+	p.matched = append(p.matched, strings.TrimSpace(p.text[start:end]))
+	p.text = p.text[:start] + " " + p.text[end:]
+}
+
+// add - sets a filter; list-valued filters (powertrain, body type) accumulate
+func (p *queryParser) add(param, value string) {
+	existing, ok := p.filters[param]
+	if !ok {
+		p.filters[param] = value
+		return
+	}
+	for _, v := range strings.Split(existing, ",") {
+		if v == value {
+			return
+		}
+	}
+	p.filters[param] = existing + "," + value
+}
+
+func (p *queryParser) parseRange() {
+	for {
+		m := rangeRe.FindStringSubmatchIndex(p.text)
+		if m == nil {
+			return
+		}
+		km, err := parseNumber(p.text[m[6]:m[7]])
+		if err == nil {
+			param := "min_range_km"
+			if m[2] >= 0 && m[8] < 0 {
+				param = "max_range_km"
+			}
+			p.filters[param] = strconv.Itoa(int(km))
+		}
+		p.consume(m[0], m[1])
+	}
+}
+
+func (p *queryParser) parseSeats() {
+	for _, re := range []*regexp.Regexp{seatsRe, seatsBeforeRe} {
+		for {
+			m := re.FindStringSubmatchIndex(p.text)
+			if m == nil {
+				break
+			}
+			p.add("seats", p.text[m[2]:m[3]])
+			p.consume(m[0], m[1])
+		}
+	}
+}
+
+func (p *queryParser) parseKeywords(list []keywordFilter) {
+	for _, kw := range list {
+		for {
+			loc := kw.re.FindStringIndex(p.text)
+			if loc == nil {
+				break
+			}
+			p.add(kw.param, kw.value)
+			p.consume(loc[0], loc[1])
+		}
+	}
+}
+
+func (p *queryParser) parsePrice() {
+	// "1-1.5 ล้าน", "between 800k and 1.2m"
+	search := 0
+	for search < len(p.text) {
+		m := betweenRe.FindStringSubmatchIndex(p.text[search:])
+		if m == nil {
+			break
+		}
+		for i := range m {
+			if m[i] >= 0 {
+				m[i] += search
+			}
+		}
+		lowUnit, highUnit := group(p.text, m, 2), group(p.text, m, 4)
+		if lowUnit == "" {
+			lowUnit = highUnit
+		}
+		low, okLow := priceValue(group(p.text, m, 1), lowUnit)
+		high, okHigh := priceValue(group(p.text, m, 3), highUnit)
+		if !okLow || !okHigh || low > high || inWord(p.text, m[2]) {
+			// Not a range; a later one in the query may still be
+			search = m[1]
+			continue
+		}
+		p.filters["min_price_baht"] = formatPrice(low)
+		p.filters["max_price_baht"] = formatPrice(high)
+		p.consume(m[0], m[1])
+	}
+
+	search = 0
+	for search < len(p.text) {
+		m := priceRe.FindStringSubmatchIndex(p.text[search:])
+		if m == nil {
+			return
+		}
+		for i := range m {
+			if m[i] >= 0 {
+				m[i] += search
+			}
+		}
+		amount, ok := priceValue(group(p.text, m, 3), group(p.text, m, 4))
+		if !ok || inWord(p.text, m[6]) {
+			// Not a price ("model 3", "x5m"), keep it as search text
+			search = m[7]
+			continue
+		}
+		param := "max_price_baht"
+		if m[4] >= 0 || m[10] >= 0 {
+			param = "min_price_baht"
+		}
+		p.filters[param] = formatPrice(amount)
+		p.consume(m[0], m[1])
+	}
+}
+
+// group - submatch i of m, or "" when it did not participate
+func group(s string, m []int, i int) string {
+	if m[2*i] < 0 {
+		return ""
+	}
+	return s[m[2*i]:m[2*i+1]]
+}
+
+func parseNumber(s string) (float64, error) {
+	return strconv.ParseFloat(strings.ReplaceAll(s, ",", ""), 64)
+}
+
+// inWord - whether the number at i continues a word, as in "x3" or "m340i"
+func inWord(s string, i int) bool {
+	if i == 0 {
+		return false
+	}
+	c := s[i-1]
+	return c >= 'a' && c <= 'z' || c >= '0' && c <= '9'
+}
+
+// priceValue - amount in baht; false when the number is not a plausible price
+func priceValue(number, unit string) (float64, bool) {
+	unit = strings.TrimSpace(unit)
+	n, err := parseNumber(number)
+	if err != nil {
+		return 0, false
+	}
+	if unit == "" {
+		return n, n >= minBarePrice
+	}
+	return n * priceMultipliers[unit], true
+}
+
+func formatPrice(v float64) string {
+	return strconv.FormatFloat(v, 'f', 0, 64)
+}
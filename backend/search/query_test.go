@@ -0,0 +1,45 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		query   string
+		filters map[string]string
+		text    string
+	}{
+		{"bmw x3 m sport", map[string]string{}, "bmw x3 m sport"},
+		{"bmw x5m", map[string]string{}, "bmw x5m"},
+		{"tesla model 3", map[string]string{}, "tesla model 3"},
+		{"mazda cx-5", map[string]string{}, "mazda cx-5"},
+		{"honda civic 1.5 m", map[string]string{}, "honda civic 1.5 m"},
+		{"7 seater suv", map[string]string{"seats": "7", "body_type": "suv"}, ""},
+		{"7 seaters", map[string]string{"seats": "7"}, ""},
+		{"seats 5 hybrid", map[string]string{"seats": "5", "powertrain_type": "HEV"}, ""},
+		{"byd atto 3 800k", map[string]string{"max_price_baht": "800000"}, "byd atto 3"},
+		{"budget 900,000", map[string]string{"max_price_baht": "900000"}, ""},
+		{"plug-in hybrid over 2m", map[string]string{"min_price_baht": "2000000", "powertrain_type": "PHEV"}, ""},
+		{"between 800k and 1.2m", map[string]string{"min_price_baht": "800000", "max_price_baht": "1200000"}, ""},
+		{"1-1.5 ล้าน", map[string]string{"min_price_baht": "1000000", "max_price_baht": "1500000"}, ""},
+		// A rejected range does not stop the scan for a later one
+		{"bmw x3-5 1-1.5 ล้าน", map[string]string{"min_price_baht": "1000000", "max_price_baht": "1500000"}, "bmw x3-5"},
+		{"รถไฟฟ้า ไม่เกิน 1 ล้าน", map[string]string{"powertrain_type": "BEV", "max_price_baht": "1000000"}, ""},
+		{
+			"EV SUV under 1.5 million with 400km range",
+			map[string]string{"powertrain_type": "BEV", "body_type": "suv", "max_price_baht": "1500000", "min_range_km": "400"},
+			"",
+		},
+	}
+	for _, tt := range tests {
+		got := ParseQuery(tt.query)
+		if !reflect.DeepEqual(got.Filters, tt.filters) {
+			t.Errorf("ParseQuery(%q).Filters = %v, want %v", tt.query, got.Filters, tt.filters)
+		}
+		if got.Text != tt.text {
+			t.Errorf("ParseQuery(%q).Text = %q, want %q", tt.query, got.Text, tt.text)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package search
+
+import (
+	"sort"
+	"strings"
+)
+
+// Suggestion - an autocomplete entry
+type Suggestion struct {
+	Kind  string  `json:"type"`
+	ID    int     `json:"id"`
+	Label string  `json:"label"`
+	Score float64 `json:"score"`
+}
+
+// Suggestion match scores; a typo costs scoreTypo per edit
+const (
+	scoreSuggestExact  = 3.0
+	scoreSuggestPrefix = 2.5
+	scoreSuggestFuzzy  = 2.0
+	scoreTypo          = 0.5
+	// unmatchedPenalty - per document token the query did not touch, so
+	// "tesla" ranks the brand above every Tesla variant
+	unmatchedPenalty = 0.1
+)
+
+// maxTypos - edits tolerated for a query token: none for very short tokens,
+// where a single edit already matches almost anything
+func maxTypos(token string) int {
+	switch n := len([]rune(token)); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	}
+	return 2
+}
+
+// Suggest - up to limit documents whose tokens all match the query, allowing
+// typos. The last query token is matched as a prefix while it is being typed.
+func (ix *Index) Suggest(q string, limit int) ([]Suggestion, error) {
+	if err := ix.refresh(); err != nil {
+		return nil, err
+	}
+
+	query := Tokenize(q)
+	out := []Suggestion{}
+	if len(query) == 0 {
+		return out, nil
+	}
+	// "tesla " - the last word is complete, no prefix matching
+	typing := !strings.HasSuffix(q, " ")
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	for _, doc := range ix.docs {
+		score := 0.0
+		matched := 0
+		for i, t := range query {
+			prefix := typing && i == len(query)-1
+			best := 0.0
+			for _, f := range doc.fields {
+				for _, token := range f.tokens {
+					if s := suggestTokenScore(t, token, prefix) * f.weight; s > best {
+						best = s
+					}
+				}
+			}
+			if best == 0 {
+				score = 0
+				break
+			}
+			score += best
+			matched++
+		}
+		if score == 0 {
+			continue
+		}
+		if extra := doc.tokens - matched; extra > 0 {
+			score /= 1 + unmatchedPenalty*float64(extra)
+		}
+		out = append(out, Suggestion{Kind: doc.kind, ID: doc.id, Label: doc.label, Score: score})
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		return out[i].Label < out[j].Label
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func suggestTokenScore(t, token string, prefix bool) float64 {
+	if t == token {
+		return scoreSuggestExact
+	}
+	if prefix && strings.HasPrefix(token, t) {
+		return scoreSuggestPrefix
+	}
+
+	typos := maxTypos(t)
+	if typos == 0 {
+		return 0
+	}
+
+	d := typos + 1
+	tr, kr := []rune(t), []rune(token)
+	if prefix {
+		// Compare against prefixes of about the typed length: "xpwe" ~ "xpow"
+		for n := len(tr) - 1; n <= len(tr)+1; n++ {
+			if n > 0 && n <= len(kr) {
+				if pd := osaDistance(tr, kr[:n]); pd < d {
+					d = pd
+				}
+			}
+		}
+	} else if abs(len(tr)-len(kr)) <= typos {
+		// Lengths in characters: a Thai character is 3 bytes
+		d = osaDistance(tr, kr)
+	}
+
+	if d > typos {
+		return 0
+	}
+	return scoreSuggestFuzzy - scoreTypo*float64(d)
+}
+
+// osaDistance - Damerau-Levenshtein distance (optimal string alignment variant):
+// insertions, deletions, substitutions and adjacent transpositions cost 1
+func osaDistance(a, b []rune) int {
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(b)]
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
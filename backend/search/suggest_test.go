@@ -0,0 +1,88 @@
+package search
+
+import "testing"
+
+func TestOSADistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"tesla", "tesla", 0},
+		{"tesla", "telsa", 1},
+		{"atto", "ato", 1},
+		{"xpeng", "xpend", 1},
+		{"mercedes", "mecredes", 1},
+		{"seal", "sela", 1},
+		{"bmw", "", 3},
+		{"โตโยตา", "โตโยต้า", 1},
+	}
+	for _, tt := range tests {
+		if got := osaDistance([]rune(tt.a), []rune(tt.b)); got != tt.want {
+			t.Errorf("osaDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSuggestTokenScore(t *testing.T) {
+	tests := []struct {
+		query, token string
+		prefix       bool
+		want         float64
+	}{
+		{"tesla", "tesla", false, scoreSuggestExact},
+		{"tes", "tesla", true, scoreSuggestPrefix},
+		{"tes", "tesla", false, 0},
+		{"telsa", "tesla", false, scoreSuggestFuzzy - scoreTypo},
+		{"xpwe", "xpeng", true, scoreSuggestFuzzy - scoreTypo},
+		// Too short for typos
+		{"bwm", "bmw", false, 0},
+		// One Thai character missing is one typo, not three bytes
+		{"โตโยตา", "โตโยต้า", false, scoreSuggestFuzzy - scoreTypo},
+		{"เมอร์ซิเดส", "เมอร์เซเดส", false, scoreSuggestFuzzy - 2*scoreTypo},
+	}
+	for _, tt := range tests {
+		if got := suggestTokenScore(tt.query, tt.token, tt.prefix); got != tt.want {
+			t.Errorf("suggestTokenScore(%q, %q, %v) = %g, want %g", tt.query, tt.token, tt.prefix, got, tt.want)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	ix := NewIndex(0, func() ([]Document, error) {
+		return []Document{
+			{Kind: "brand", ID: 1, Label: "Toyota", Fields: []Field{{"Toyota", WeightBrand}, {"โตโยต้า", WeightBrand}}},
+			{Kind: "model", ID: 2, Label: "Toyota Yaris", Fields: []Field{{"Toyota", WeightBrand}, {"Yaris", WeightModel}}},
+			{Kind: "brand", ID: 3, Label: "Tesla", Fields: []Field{{"Tesla", WeightBrand}}},
+		}, nil
+	})
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"toy", []int{1, 2}},
+		{"toyota ya", []int{2}},
+		{"telsa ", []int{3}},
+		{"โตโยตา ", []int{1}},
+	}
+	for _, tt := range tests {
+		got, err := ix.Suggest(tt.query, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids := make([]int, len(got))
+		for i, s := range got {
+			ids[i] = s.ID
+		}
+		if len(ids) != len(tt.want) {
+			t.Errorf("Suggest(%q) = %v, want %v", tt.query, ids, tt.want)
+			continue
+		}
+		for i := range ids {
+			if ids[i] != tt.want[i] {
+				t.Errorf("Suggest(%q) = %v, want %v", tt.query, ids, tt.want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strings"
+)
+
+// ErrInvalidKey - keys are relative slash-separated paths without "." or ".." segments
+var ErrInvalidKey = errors.New("invalid storage key")
+
+// Store - where uploaded files live. Put returns the public URL of the stored object.
+type Store interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) (string, error)
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+var current Store = NewLocal("uploads", "/media")
+
+// Current - the store selected by Init
+func Current() Store {
+	return current
+}
+
+// Init selects the backend from STORAGE_BACKEND: "local" (default, files under
+// STORAGE_LOCAL_DIR served from /media) or "s3" (any S3-compatible service,
+// configured by the S3_* variables).
+func Init() {
+	switch backend := os.Getenv("STORAGE_BACKEND"); backend {
+	case "", "local":
+		dir := os.Getenv("STORAGE_LOCAL_DIR")
+		if dir == "" {
+			dir = "uploads"
+		}
+		publicURL := os.Getenv("STORAGE_PUBLIC_URL")
+		if publicURL == "" {
+			publicURL = "/media"
+		}
+		current = NewLocal(dir, publicURL)
+		log.Println("✅ Media storage: local directory", dir)
+	case "s3":
+		s, err := S3FromEnv()
+		if err != nil {
+			log.Fatal("❌ Invalid S3 storage config: ", err)
+		}
+		current = s
+		log.Println("✅ Media storage: S3 bucket", s.Bucket)
+	default:
+		log.Fatalf("❌ Unknown STORAGE_BACKEND %q (use local or s3)", backend)
+	}
+}
+
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") {
+		return false
+	}
+	for _, seg := range strings.Split(key, "/") {
+		if seg == "" || seg == "." || seg == ".." {
+			return false
+		}
+	}
+	return true
+}
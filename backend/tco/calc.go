@@ -0,0 +1,213 @@
+package tco
+
+import "math"
+
+// Inputs - what the buyer tells us about their usage
+type Inputs struct {
+	KmPerYear int `json:"km_per_year"`
+	Years     int `json:"years"`
+	// ElectricityPrice - home charging, baht per kWh
+	ElectricityPrice float64 `json:"electricity_price"`
+	// DCPrice - public DC fast charging, baht per kWh
+	DCPrice float64 `json:"dc_price"`
+	// HomeShare - share of charging done at home (0-1), the rest is DC
+	HomeShare float64 `json:"home_share"`
+	// FuelPrice - baht per liter
+	FuelPrice float64 `json:"fuel_price"`
+	// PHEVElectricShare - share of PHEV kilometers driven on electricity (0-1)
+	PHEVElectricShare float64 `json:"phev_electric_share"`
+}
+
+// Vehicle - the specs a TCO calculation needs; nil = unknown
+type Vehicle struct {
+	PowertrainType     string
+	PriceBaht          *float64
+	BatteryCapacityKwh *float64
+	RangeKm            *int
+	EvRangeKm          *int
+	FuelConsumptionKml *float64
+	DisplacementCc     *int
+	CurbWeightKg       *int
+}
+
+// YearCost - running costs of one year of ownership, in baht
+type YearCost struct {
+	Year        int     `json:"year"`
+	Energy      float64 `json:"energy"`
+	Insurance   float64 `json:"insurance"`
+	Tax         float64 `json:"tax"`
+	Maintenance float64 `json:"maintenance"`
+	Total       float64 `json:"total"`
+	// Cumulative - purchase price plus every year so far
+	Cumulative float64 `json:"cumulative"`
+}
+
+// Totals - sums over the whole ownership period
+type Totals struct {
+	Energy      float64 `json:"energy"`
+	Insurance   float64 `json:"insurance"`
+	Tax         float64 `json:"tax"`
+	Maintenance float64 `json:"maintenance"`
+	Running     float64 `json:"running"`
+	Total       float64 `json:"total"`
+}
+
+// Result - TCO of one vehicle
+type Result struct {
+	PurchasePrice   float64    `json:"purchase_price"`
+	EnergyCostPerKm *float64   `json:"energy_cost_per_km"`
+	Years           []YearCost `json:"years"`
+	Totals          Totals     `json:"totals"`
+	CostPerKm       float64    `json:"cost_per_km"`
+	// Warnings - specs that were missing and left out of the calculation
+	Warnings []string `json:"warnings"`
+}
+
+// Calculate - year-by-year cost of owning v under the given inputs and tables
+func Calculate(v Vehicle, in Inputs, t *Tables) Result {
+	r := Result{Years: make([]YearCost, 0, in.Years), Warnings: []string{}}
+
+	if v.PriceBaht != nil {
+		r.PurchasePrice = *v.PriceBaht
+	} else {
+		r.Warnings = append(r.Warnings, "price_baht is missing: purchase price and insurance are not included")
+	}
+
+	perKm, warnings := energyCostPerKm(v, in, t)
+	r.Warnings = append(r.Warnings, warnings...)
+	if perKm != nil {
+		rounded := round(*perKm, 2)
+		r.EnergyCostPerKm = &rounded
+	}
+
+	annualTax, taxByCC, warning := baseTax(v, t)
+	if warning != "" {
+		r.Warnings = append(r.Warnings, warning)
+	}
+
+	cumulative := r.PurchasePrice
+	for year := 1; year <= in.Years; year++ {
+		y := YearCost{Year: year}
+		if perKm != nil {
+			y.Energy = *perKm * float64(in.KmPerYear)
+		}
+		y.Insurance = t.CompulsoryInsurance
+		if v.PriceBaht != nil {
+			y.Insurance += *v.PriceBaht * yearValue(t.Insurance[v.PowertrainType], year) / 100
+		}
+		y.Tax = annualTax
+		if taxByCC && year >= t.Tax.AgeDiscountMin && t.Tax.AgeDiscountMin > 0 {
+			y.Tax *= 1 - yearValue(t.Tax.AgeDiscount, year-t.Tax.AgeDiscountMin+1)
+		}
+		y.Maintenance = yearValue(t.Maintenance[v.PowertrainType], year)
+
+		y.Energy, y.Insurance, y.Tax, y.Maintenance = round(y.Energy, 0), round(y.Insurance, 0), round(y.Tax, 0), round(y.Maintenance, 0)
+		y.Total = y.Energy + y.Insurance + y.Tax + y.Maintenance
+		cumulative += y.Total
+		y.Cumulative = cumulative
+		r.Years = append(r.Years, y)
+
+		r.Totals.Energy += y.Energy
+		r.Totals.Insurance += y.Insurance
+		r.Totals.Tax += y.Tax
+		r.Totals.Maintenance += y.Maintenance
+	}
+	r.Totals.Running = r.Totals.Energy + r.Totals.Insurance + r.Totals.Tax + r.Totals.Maintenance
+	r.Totals.Total = r.PurchasePrice + r.Totals.Running
+
+	if km := in.KmPerYear * in.Years; km > 0 {
+		r.CostPerKm = round(r.Totals.Total/float64(km), 2)
+	}
+	return r
+}
+
+// energyCostPerKm - baht per km for electricity and/or fuel
+func energyCostPerKm(v Vehicle, in Inputs, t *Tables) (*float64, []string) {
+	electricityPrice := in.HomeShare*in.ElectricityPrice + (1-in.HomeShare)*in.DCPrice
+	efficiency := t.ChargingEfficiency
+	if efficiency <= 0 {
+		efficiency = 1
+	}
+
+	// kWh drawn from the grid per km, from the rated range of a full battery
+	kwhPerKm := func(rangeKm *int) (float64, bool) {
+		if v.BatteryCapacityKwh == nil || rangeKm == nil || *rangeKm <= 0 {
+			return 0, false
+		}
+		return *v.BatteryCapacityKwh / float64(*rangeKm) / efficiency, true
+	}
+	fuelPerKm := func() (float64, bool) {
+		if v.FuelConsumptionKml == nil || *v.FuelConsumptionKml <= 0 {
+			return 0, false
+		}
+		return in.FuelPrice / *v.FuelConsumptionKml, true
+	}
+
+	switch v.PowertrainType {
+	case "BEV":
+		kwh, ok := kwhPerKm(v.RangeKm)
+		if !ok {
+			return nil, []string{"battery_capacity_kwh or range_km is missing: energy cost is not included"}
+		}
+		cost := kwh * electricityPrice
+		return &cost, nil
+	case "PHEV":
+		fuel, okFuel := fuelPerKm()
+		kwh, okElectric := kwhPerKm(v.EvRangeKm)
+		switch {
+		case okFuel && okElectric:
+			cost := in.PHEVElectricShare*kwh*electricityPrice + (1-in.PHEVElectricShare)*fuel
+			return &cost, nil
+		case okFuel:
+			return &fuel, []string{"battery_capacity_kwh or ev_range_km is missing: all kilometers are costed on fuel"}
+		}
+		return nil, []string{"fuel_consumption_kml is missing: energy cost is not included"}
+	}
+
+	fuel, ok := fuelPerKm()
+	if !ok {
+		return nil, []string{"fuel_consumption_kml is missing: energy cost is not included"}
+	}
+	return &fuel, nil
+}
+
+// baseTax - annual tax before age discounts; taxByCC tells whether age
+// discounts apply
+func baseTax(v Vehicle, t *Tables) (tax float64, taxByCC bool, warning string) {
+	if v.PowertrainType == "BEV" {
+		if v.CurbWeightKg == nil {
+			return 0, false, "curb_weight_kg is missing: tax is not included"
+		}
+		for _, band := range t.Tax.WeightBands {
+			if band.UpTo == 0 || *v.CurbWeightKg <= band.UpTo {
+				return band.Rate * (1 - t.Tax.BEVDiscount), false, ""
+			}
+		}
+		return 0, false, ""
+	}
+
+	if v.DisplacementCc == nil {
+		return 0, false, "displacement_cc is missing: tax is not included"
+	}
+	cc := *v.DisplacementCc
+	lower := 0
+	for _, band := range t.Tax.CCBands {
+		upper := band.UpTo
+		if upper == 0 || upper > cc {
+			upper = cc
+		}
+		if upper > lower {
+			tax += float64(upper-lower) * band.Rate
+		}
+		if band.UpTo == 0 || band.UpTo >= cc {
+			break
+		}
+		lower = band.UpTo
+	}
+	return tax, true, ""
+}
+
+func round(x float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(x*p) / p
+}
@@ -0,0 +1,143 @@
+package tco
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func fp(v float64) *float64 { return &v }
+func ip(v int) *int         { return &v }
+
+func TestBaseTax(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       Vehicle
+		tax     float64
+		taxByCC bool
+		warning bool
+	}{
+		{"first band only", Vehicle{PowertrainType: "ICE", DisplacementCc: ip(500)}, 250, true, false},
+		{"band edge", Vehicle{PowertrainType: "ICE", DisplacementCc: ip(600)}, 300, true, false},
+		{"two bands", Vehicle{PowertrainType: "HEV", DisplacementCc: ip(1500)}, 300 + 900*1.5, true, false},
+		{"open-ended band", Vehicle{PowertrainType: "ICE", DisplacementCc: ip(2400)}, 300 + 1200*1.5 + 600*4, true, false},
+		{"BEV by weight with discount", Vehicle{PowertrainType: "BEV", CurbWeightKg: ip(1800)}, 1600 * 0.2, false, false},
+		{"BEV heavier than every band", Vehicle{PowertrainType: "BEV", CurbWeightKg: ip(9000)}, 3600 * 0.2, false, false},
+		{"missing displacement", Vehicle{PowertrainType: "ICE"}, 0, false, true},
+		{"missing weight", Vehicle{PowertrainType: "BEV", DisplacementCc: ip(0)}, 0, false, true},
+	}
+	for _, tt := range tests {
+		tax, taxByCC, warning := baseTax(tt.v, DefaultTables())
+		if math.Abs(tax-tt.tax) > 1e-9 || taxByCC != tt.taxByCC || (warning != "") != tt.warning {
+			t.Errorf("%s: baseTax = %g %v %q, want %g %v warning=%v", tt.name, tax, taxByCC, warning, tt.tax, tt.taxByCC, tt.warning)
+		}
+	}
+}
+
+func TestEnergyCostPerKm(t *testing.T) {
+	tables := DefaultTables()
+	// 80% home at 4.5 + 20% DC at 7.5
+	const electricity = 5.1
+
+	tests := []struct {
+		name     string
+		v        Vehicle
+		want     *float64
+		warnings int
+	}{
+		{"BEV", Vehicle{PowertrainType: "BEV", BatteryCapacityKwh: fp(60), RangeKm: ip(400)}, fp(60.0 / 400 / 0.9 * electricity), 0},
+		{"BEV without range", Vehicle{PowertrainType: "BEV", BatteryCapacityKwh: fp(60)}, nil, 1},
+		{"BEV with zero range", Vehicle{PowertrainType: "BEV", BatteryCapacityKwh: fp(60), RangeKm: ip(0)}, nil, 1},
+		{"ICE", Vehicle{PowertrainType: "ICE", FuelConsumptionKml: fp(15)}, fp(38.0 / 15), 0},
+		{"ICE without consumption", Vehicle{PowertrainType: "ICE"}, nil, 1},
+		{"PHEV", Vehicle{PowertrainType: "PHEV", BatteryCapacityKwh: fp(18), EvRangeKm: ip(90), FuelConsumptionKml: fp(20)},
+			fp(0.5*18.0/90/0.9*electricity + 0.5*38.0/20), 0},
+		{"PHEV on fuel only", Vehicle{PowertrainType: "PHEV", FuelConsumptionKml: fp(20)}, fp(38.0 / 20), 1},
+		{"PHEV without consumption", Vehicle{PowertrainType: "PHEV", BatteryCapacityKwh: fp(18), EvRangeKm: ip(90)}, nil, 1},
+	}
+	for _, tt := range tests {
+		got, warnings := energyCostPerKm(tt.v, tables.Defaults, tables)
+		switch {
+		case (got == nil) != (tt.want == nil):
+			t.Errorf("%s: energyCostPerKm = %v, want %v", tt.name, got, tt.want)
+		case got != nil && math.Abs(*got-*tt.want) > 1e-9:
+			t.Errorf("%s: energyCostPerKm = %g, want %g", tt.name, *got, *tt.want)
+		}
+		if len(warnings) != tt.warnings {
+			t.Errorf("%s: warnings = %q, want %d", tt.name, warnings, tt.warnings)
+		}
+	}
+}
+
+func TestCalculate(t *testing.T) {
+	tables := DefaultTables()
+	in := tables.Defaults
+	in.Years = 7
+	v := Vehicle{PowertrainType: "ICE", PriceBaht: fp(1000000), FuelConsumptionKml: fp(15), DisplacementCc: ip(1500)}
+
+	r := Calculate(v, in, tables)
+
+	// Energy 38 / 15 * 20000; insurance 645 + % of price; tax 1650 with the
+	// age discount from year 6; maintenance from the table, last entry repeating
+	want := []YearCost{
+		{Year: 1, Energy: 50667, Insurance: 22645, Tax: 1650, Maintenance: 4000, Total: 78962, Cumulative: 1078962},
+		{Year: 2, Energy: 50667, Insurance: 20645, Tax: 1650, Maintenance: 7000, Total: 79962, Cumulative: 1158924},
+		{Year: 3, Energy: 50667, Insurance: 18645, Tax: 1650, Maintenance: 7000, Total: 77962, Cumulative: 1236886},
+		{Year: 4, Energy: 50667, Insurance: 16645, Tax: 1650, Maintenance: 10000, Total: 78962, Cumulative: 1315848},
+		{Year: 5, Energy: 50667, Insurance: 15645, Tax: 1650, Maintenance: 14000, Total: 81962, Cumulative: 1397810},
+		{Year: 6, Energy: 50667, Insurance: 15645, Tax: 1485, Maintenance: 14000, Total: 81797, Cumulative: 1479607},
+		{Year: 7, Energy: 50667, Insurance: 15645, Tax: 1320, Maintenance: 14000, Total: 81632, Cumulative: 1561239},
+	}
+	if !reflect.DeepEqual(r.Years, want) {
+		t.Errorf("Years = %+v, want %+v", r.Years, want)
+	}
+	if r.Totals.Running != 561239 || r.Totals.Total != 1561239 {
+		t.Errorf("Totals = %+v, want running 561239, total 1561239", r.Totals)
+	}
+	if r.EnergyCostPerKm == nil || *r.EnergyCostPerKm != 2.53 {
+		t.Errorf("EnergyCostPerKm = %v, want 2.53", r.EnergyCostPerKm)
+	}
+	if r.CostPerKm != 11.15 {
+		t.Errorf("CostPerKm = %g, want 11.15", r.CostPerKm)
+	}
+	if len(r.Warnings) != 0 {
+		t.Errorf("Warnings = %q, want none", r.Warnings)
+	}
+}
+
+func TestCalculateMissingSpecs(t *testing.T) {
+	tables := DefaultTables()
+	r := Calculate(Vehicle{PowertrainType: "BEV"}, tables.Defaults, tables)
+
+	if len(r.Warnings) != 3 {
+		t.Errorf("Warnings = %q, want price, energy and tax", r.Warnings)
+	}
+	if r.EnergyCostPerKm != nil || r.PurchasePrice != 0 {
+		t.Errorf("EnergyCostPerKm = %v, PurchasePrice = %g, want nil and 0", r.EnergyCostPerKm, r.PurchasePrice)
+	}
+	// Compulsory insurance and maintenance do not depend on specs
+	if y := r.Years[0]; y.Energy != 0 || y.Tax != 0 || y.Insurance != 645 || y.Maintenance != 1500 {
+		t.Errorf("Years[0] = %+v, want insurance 645 and maintenance 1500 only", y)
+	}
+	if len(r.Years) != tables.Defaults.Years {
+		t.Errorf("len(Years) = %d, want %d", len(r.Years), tables.Defaults.Years)
+	}
+}
+
+func TestYearValue(t *testing.T) {
+	tests := []struct {
+		values []float64
+		year   int
+		want   float64
+	}{
+		{[]float64{1, 2, 3}, 1, 1},
+		{[]float64{1, 2, 3}, 3, 3},
+		{[]float64{1, 2, 3}, 9, 3},
+		{nil, 1, 0},
+	}
+	for _, tt := range tests {
+		if got := yearValue(tt.values, tt.year); got != tt.want {
+			t.Errorf("yearValue(%v, %d) = %g, want %g", tt.values, tt.year, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package tco
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+)
+
+// Tables - the assumptions behind a TCO calculation. Defaults below are Thai
+// market ballparks; every value can be overridden from a JSON file named by
+// TCO_TABLES_PATH (same shape as the json tags, missing keys keep the default).
+type Tables struct {
+	Defaults Inputs `json:"defaults"`
+	// Insurance - voluntary insurance premium as % of the purchase price, by
+	// powertrain and year of ownership (the last entry repeats)
+	Insurance map[string][]float64 `json:"insurance"`
+	// CompulsoryInsurance - compulsory motor insurance, baht per year
+	CompulsoryInsurance float64 `json:"compulsory_insurance"`
+	// Maintenance - scheduled maintenance, baht per year of ownership by
+	// powertrain (the last entry repeats)
+	Maintenance map[string][]float64 `json:"maintenance"`
+	Tax         TaxTable             `json:"tax"`
+	// ChargingEfficiency - share of grid energy that ends up in the battery
+	ChargingEfficiency float64 `json:"charging_efficiency"`
+}
+
+// TaxTable - annual vehicle tax. Combustion and hybrid cars pay per cc in
+// bands; BEVs pay by curb weight with a discount.
+type TaxTable struct {
+	CCBands        []TaxBand `json:"cc_bands"`
+	WeightBands    []TaxBand `json:"weight_bands"`
+	BEVDiscount    float64   `json:"bev_discount"`
+	AgeDiscountMin int       `json:"age_discount_from_year"`
+	// AgeDiscount - discount on the cc-based tax from AgeDiscountMin onwards,
+	// one entry per year (the last entry repeats)
+	AgeDiscount []float64 `json:"age_discount"`
+}
+
+// TaxBand - UpTo is the inclusive upper bound of the band (0 = no limit).
+// In CCBands Rate is baht per cc inside the band; in WeightBands it is the flat
+// annual tax for a car up to that weight.
+type TaxBand struct {
+	UpTo int     `json:"up_to"`
+	Rate float64 `json:"rate"`
+}
+
+var current = DefaultTables()
+
+// Current - the tables in use
+func Current() *Tables {
+	return current
+}
+
+// Init applies TCO_TABLES_PATH on top of the defaults, if set.
+func Init() {
+	path := os.Getenv("TCO_TABLES_PATH")
+	if path == "" {
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatal("❌ Failed to read TCO tables: ", err)
+	}
+	t := DefaultTables()
+	if err := json.Unmarshal(data, t); err != nil {
+		log.Fatal("❌ Invalid TCO tables: ", err)
+	}
+	current = t
+	log.Println("✅ TCO tables loaded from", path)
+}
+
+// DefaultTables - built-in assumptions
+func DefaultTables() *Tables {
+	return &Tables{
+		Defaults: Inputs{
+			KmPerYear:         20000,
+			Years:             5,
+			ElectricityPrice:  4.5,
+			DCPrice:           7.5,
+			HomeShare:         0.8,
+			FuelPrice:         38,
+			PHEVElectricShare: 0.5,
+		},
+		Insurance: map[string][]float64{
+			"BEV":  {3.0, 2.6, 2.3, 2.0, 1.8},
+			"PHEV": {2.6, 2.3, 2.0, 1.8, 1.6},
+			"HEV":  {2.2, 2.0, 1.8, 1.6, 1.5},
+			"MHEV": {2.2, 2.0, 1.8, 1.6, 1.5},
+			"ICE":  {2.2, 2.0, 1.8, 1.6, 1.5},
+		},
+		CompulsoryInsurance: 645,
+		Maintenance: map[string][]float64{
+			"BEV":  {1500, 3000, 3000, 4500, 6000},
+			"PHEV": {3000, 4500, 4500, 7000, 9000},
+			"HEV":  {3000, 5000, 5000, 8000, 10000},
+			"MHEV": {3500, 6000, 6000, 9000, 12000},
+			"ICE":  {4000, 7000, 7000, 10000, 14000},
+		},
+		Tax: TaxTable{
+			CCBands: []TaxBand{
+				{UpTo: 600, Rate: 0.5},
+				{UpTo: 1800, Rate: 1.5},
+				{UpTo: 0, Rate: 4},
+			},
+			WeightBands: []TaxBand{
+				{UpTo: 500, Rate: 150}, {UpTo: 750, Rate: 300}, {UpTo: 1000, Rate: 450},
+				{UpTo: 1250, Rate: 800}, {UpTo: 1500, Rate: 1000}, {UpTo: 1750, Rate: 1300},
+				{UpTo: 2000, Rate: 1600}, {UpTo: 2500, Rate: 1900}, {UpTo: 3000, Rate: 2200},
+				{UpTo: 3500, Rate: 2400}, {UpTo: 4000, Rate: 2600}, {UpTo: 4500, Rate: 2800},
+				{UpTo: 5000, Rate: 3000}, {UpTo: 6000, Rate: 3200}, {UpTo: 7000, Rate: 3400},
+				{UpTo: 0, Rate: 3600},
+			},
+			BEVDiscount:    0.8,
+			AgeDiscountMin: 6,
+			AgeDiscount:    []float64{0.1, 0.2, 0.3, 0.4, 0.5},
+		},
+		ChargingEfficiency: 0.9,
+	}
+}
+
+// yearValue - entry for ownership year (1-based); the last entry repeats
+func yearValue(values []float64, year int) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	if year > len(values) {
+		year = len(values)
+	}
+	return values[year-1]
+}
@@ -162,13 +162,8 @@ class ApiService {
       if (powertrainType != null) params['powertrain_type'] = powertrainType;
       if (bodyType != null) params['body_type'] = bodyType;
 
-      final uri = Uri.parse('$baseUrl/cars/models').replace(queryParameters: params.isNotEmpty ? params : null);
-      final response = await http.get(uri);
-      if (response.statusCode == 200) {
-        List<dynamic> body = json.decode(response.body);
-        return body.map((item) => CarModel.fromJson(item)).toList();
-      }
-      return [];
+      final body = await _getAllPages('$baseUrl/cars/models', params);
+      return body.map((item) => CarModel.fromJson(item)).toList();
     } catch (e) {
       print('Error fetching car models: $e');
       return [];
@@ -221,7 +216,7 @@ class ApiService {
     try {
       final response = await http.get(Uri.parse('$baseUrl/cars/search?q=${Uri.encodeComponent(q)}'));
       if (response.statusCode == 200) {
-        List<dynamic> body = json.decode(response.body);
+        List<dynamic> body = json.decode(response.body)['data'];
         return body.map((item) => CarSearchResult.fromJson(item)).toList();
       }
       return [];
@@ -244,8 +239,8 @@ class ApiService {
       final response = await http.get(uri);
       if (response.statusCode == 200) {
         final body = json.decode(response.body);
-        if (body == null) return [];
-        List<dynamic> list = body;
+        if (body == null || body['data'] == null) return [];
+        List<dynamic> list = body['data'];
         return list.map((item) => CarSearchResult.fromJson(item)).toList();
       }
       return [];
@@ -257,24 +252,35 @@ class ApiService {
 
   Future<List<Item>> getItems({int? categoryId}) async {
     try {
-      String url = '$baseUrl/items';
-      if (categoryId != null) {
-        url += '?category_id=$categoryId';
-      }
-      
-      print('🔍 Fetching items from: $url');
-      final response = await http.get(Uri.parse(url));
-      
-      print('✅ Status: ${response.statusCode}');
-      
-      if (response.statusCode == 200) {
-        List<dynamic> body = json.decode(response.body);
-        return body.map((item) => Item.fromJson(item)).toList();
-      }
-      return [];
+      final params = <String, String>{};
+      if (categoryId != null) params['category_id'] = categoryId.toString();
+
+      print('🔍 Fetching items from: $baseUrl/items');
+      final body = await _getAllPages('$baseUrl/items', params);
+      return body.map((item) => Item.fromJson(item)).toList();
     } catch (e) {
       print('❌ Error: $e');
       return [];
     }
   }
+
+  /// Reads every page of a paginated list endpoint, following next_cursor
+  /// until the server returns null.
+  Future<List<dynamic>> _getAllPages(String url, Map<String, String> params) async {
+    final rows = <dynamic>[];
+    String? cursor;
+    do {
+      final query = {...params, 'limit': '100'};
+      if (cursor != null) query['cursor'] = cursor;
+
+      final response = await http.get(Uri.parse(url).replace(queryParameters: query));
+      if (response.statusCode != 200) {
+        throw Exception('GET $url failed with status ${response.statusCode}');
+      }
+      final body = json.decode(response.body);
+      rows.addAll(body['data'] as List<dynamic>);
+      cursor = body['next_cursor'] as String?;
+    } while (cursor != null);
+    return rows;
+  }
 }